@@ -0,0 +1,247 @@
+package availability
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/library"
+	"prisco.dev/spotify-playlist/matcher"
+)
+
+const (
+	// StatusRelinked tracks are played from another release of the song
+	StatusRelinked = "relinked"
+	// StatusUnavailable tracks cannot be played, they are greyed out
+	StatusUnavailable = "unavailable"
+)
+
+// ReasonRemoved is the reason of the items which were removed from Spotify
+const ReasonRemoved = "removed"
+
+// DefaultMinScore is the matcher score above which a search result is
+// considered the same song as an unavailable track without ISRC
+const DefaultMinScore = 0.9
+
+const searchLimit = 10
+
+// Item is a playlist item which is not played as added
+type Item struct {
+	Position int          `json:"position"`
+	Status   string       `json:"status"`
+	Track    export.Track `json:"track"`
+	// LinkedFrom is the uri of the added track when Spotify relinked it
+	LinkedFrom string `json:"linked_from,omitempty"`
+	// Reason is the restriction of an unavailable track: market, product,
+	// explicit or removed
+	Reason string `json:"reason,omitempty"`
+	// Alternative is a playable release of an unavailable track
+	Alternative *export.Track `json:"alternative,omitempty"`
+}
+
+type Report struct {
+	Playlist export.Playlist `json:"playlist"`
+	Market   string          `json:"market"`
+	Checked  int             `json:"checked"`
+	Items    []Item          `json:"items"`
+}
+
+// Count returns the number of items of the status
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+type ItemsClient interface {
+	PlaylistItemsInMarket(playlistId string, market string, handle func(api.PlaylistItem) error) error
+}
+
+// Check reports the relinked and unavailable tracks of the playlist in the
+// market. Episodes and local files are not checked.
+func Check(client ItemsClient, playlist export.Playlist, market string) (*Report, error) {
+	report := &Report{Playlist: playlist, Market: market, Items: []Item{}}
+
+	position := 0
+	err := client.PlaylistItemsInMarket(playlist.Id, market, func(playlistItem api.PlaylistItem) error {
+		defer func() { position++ }()
+
+		switch content := playlistItem.Item.(type) {
+		case api.Unavailable:
+			report.Checked++
+			report.Items = append(report.Items, Item{
+				Position: position,
+				Status:   StatusUnavailable,
+				Track:    export.Track{Position: position},
+				Reason:   ReasonRemoved,
+			})
+		case *api.Track:
+			report.Checked++
+			if item, ok := check(position, *content); ok {
+				report.Items = append(report.Items, item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check playlist %s: %w", playlist.Id, err)
+	}
+
+	return report, nil
+}
+
+func check(position int, track api.Track) (Item, bool) {
+	item := Item{Position: position, Track: library.ToTrack(position, track)}
+
+	switch {
+	case track.IsPlayable != nil && !*track.IsPlayable:
+		item.Status = StatusUnavailable
+		if track.Restrictions != nil {
+			item.Reason = track.Restrictions.Reason
+		}
+	case track.LinkedFrom != nil:
+		item.Status = StatusRelinked
+		item.LinkedFrom = track.LinkedFrom.Uri
+	default:
+		return Item{}, false
+	}
+
+	return item, true
+}
+
+type Searcher interface {
+	SearchTracksInMarket(query string, market string, limit int) ([]api.Track, error)
+}
+
+// FindAlternatives looks up a playable release of the unavailable tracks,
+// sharing their ISRC or else matching their title, artists and duration
+func FindAlternatives(searcher Searcher, report *Report) error {
+	trackMatcher := matcher.NewMatcher()
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		if item.Status != StatusUnavailable || item.Track.Uri == "" {
+			continue
+		}
+
+		query := "track:" + api.QuoteSearch(item.Track.Name)
+		if len(item.Track.Artists) > 0 {
+			query += " artist:" + api.QuoteSearch(item.Track.Artists[0])
+		}
+		if item.Track.Isrc != "" {
+			query = "isrc:" + item.Track.Isrc
+		}
+
+		tracks, err := searcher.SearchTracksInMarket(query, report.Market, searchLimit)
+		if err != nil {
+			return fmt.Errorf("failed to search an alternative to %s: %w", item.Track.Uri, err)
+		}
+
+		for _, candidate := range tracks {
+			if candidate.Uri == item.Track.Uri || (candidate.IsPlayable != nil && !*candidate.IsPlayable) {
+				continue
+			}
+
+			result := trackMatcher.Score(toMatcherTrack(item.Track), toMatcherTrack(library.ToTrack(0, candidate)))
+			if result.Score >= DefaultMinScore {
+				alternative := library.ToTrack(item.Position, candidate)
+				item.Alternative = &alternative
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+type Editor interface {
+	RemoveItems(playlistId string, snapshotId string, items []api.ItemPositions) (string, error)
+	InsertItems(playlistId string, uris []string, position int) (string, error)
+}
+
+// Swap replaces the unavailable tracks by their alternative at the same
+// position, starting from the last one so that the others do not shift,
+// and returns the number of swapped tracks
+func Swap(editor Editor, report *Report) (int, error) {
+	var items []Item
+	for _, item := range report.Items {
+		if item.Alternative != nil {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Position > items[j].Position
+	})
+
+	snapshotId := report.Playlist.SnapshotId
+	for swapped, item := range items {
+		removed := []api.ItemPositions{{Uri: item.Track.Uri, Positions: []int{item.Position}}}
+		newSnapshotId, err := editor.RemoveItems(report.Playlist.Id, snapshotId, removed)
+		if err != nil {
+			return swapped, fmt.Errorf("failed to remove %s from playlist %s: %w", item.Track.Uri, report.Playlist.Id, err)
+		}
+		snapshotId = newSnapshotId
+
+		newSnapshotId, err = editor.InsertItems(report.Playlist.Id, []string{item.Alternative.Uri}, item.Position)
+		if err != nil {
+			return swapped, fmt.Errorf("failed to insert %s into playlist %s: %w", item.Alternative.Uri, report.Playlist.Id, err)
+		}
+		snapshotId = newSnapshotId
+	}
+
+	return len(items), nil
+}
+
+// PrintReport writes a line per relinked or unavailable track followed by
+// the totals
+func PrintReport(writer io.Writer, report *Report) error {
+	var out strings.Builder
+	for _, item := range report.Items {
+		fmt.Fprintf(&out, "%s #%d %s: ", report.Playlist.Name, item.Position+1, describe(item.Track))
+		switch {
+		case item.Status == StatusRelinked:
+			fmt.Fprintf(&out, "relinked from %s", item.LinkedFrom)
+		case item.Reason != "":
+			fmt.Fprintf(&out, "unavailable (%s)", item.Reason)
+		default:
+			out.WriteString("unavailable")
+		}
+		if item.Alternative != nil {
+			fmt.Fprintf(&out, ", alternative %s %s", describe(*item.Alternative), item.Alternative.Uri)
+		}
+		out.WriteString("\n")
+	}
+	fmt.Fprintf(
+		&out, "%s: %d checked, %d unavailable, %d relinked\n",
+		report.Playlist.Name, report.Checked, report.Count(StatusUnavailable), report.Count(StatusRelinked),
+	)
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func describe(track export.Track) string {
+	if track.Name == "" {
+		return "removed track"
+	}
+
+	return strings.Join(track.Artists, ", ") + " - " + track.Name
+}
+
+func toMatcherTrack(track export.Track) matcher.Track {
+	return matcher.Track{
+		Id:         track.Id,
+		Title:      track.Name,
+		Artists:    track.Artists,
+		DurationMs: track.DurationMs,
+		Isrc:       track.Isrc,
+	}
+}
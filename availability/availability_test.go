@@ -0,0 +1,165 @@
+package availability
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Client serving the items of a playlist, searching and recording the edits
+type mockClient struct {
+	items   []api.PlaylistItem
+	results map[string][]api.Track
+	market  string
+	calls   []string
+}
+
+func (m *mockClient) PlaylistItemsInMarket(playlistId string, market string, handle func(api.PlaylistItem) error) error {
+	m.market = market
+	for _, item := range m.items {
+		if err := handle(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) SearchTracksInMarket(query string, market string, limit int) ([]api.Track, error) {
+	return m.results[query], nil
+}
+
+func (m *mockClient) RemoveItems(playlistId string, snapshotId string, items []api.ItemPositions) (string, error) {
+	m.calls = append(m.calls, fmt.Sprintf("remove %s@%d from %s", items[0].Uri, items[0].Positions[0], snapshotId))
+	return snapshotId + "+", nil
+}
+
+func (m *mockClient) InsertItems(playlistId string, uris []string, position int) (string, error) {
+	m.calls = append(m.calls, fmt.Sprintf("insert %s@%d", uris[0], position))
+	return "inserted", nil
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("it should report the relinked and unavailable tracks", func(t *testing.T) {
+		// Given a playlist with a playable, a relinked, a restricted and a removed track and an episode
+		client := &mockClient{items: []api.PlaylistItem{
+			{Item: &api.Track{Id: "t1", IsPlayable: playable(true)}},
+			{Item: &api.Track{Id: "t2", IsPlayable: playable(true), LinkedFrom: &api.LinkedTrack{Uri: "spotify:track:old"}}},
+			{Item: &api.Track{Id: "t3", IsPlayable: playable(false), Restrictions: &api.Restrictions{Reason: "market"}}},
+			{Item: api.Unavailable{}},
+			{Item: &api.Episode{Id: "e1"}},
+		}}
+
+		// When checking it
+		report, err := Check(client, export.Playlist{Id: "p1"}, api.MarketFromToken)
+
+		// Then the tracks should be checked in the market of the user
+		if err != nil || client.market != api.MarketFromToken || report.Checked != 4 {
+			t.Fatalf("Unexpected report %+v, %v", report, err)
+		}
+
+		// and the relinked and unavailable ones reported
+		var statuses []string
+		for _, item := range report.Items {
+			statuses = append(statuses, fmt.Sprintf("%d %s %s%s", item.Position, item.Status, item.Reason, item.LinkedFrom))
+		}
+		expected := []string{"1 relinked spotify:track:old", "2 unavailable market", "3 unavailable removed"}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("Expected %v, got %v", expected, statuses)
+		}
+	})
+}
+
+func TestFindAlternatives(t *testing.T) {
+	t.Run("it should find a playable release by ISRC or by title", func(t *testing.T) {
+		// Given unavailable tracks with and without ISRC
+		withIsrc := export.Track{Uri: "spotify:track:a", Name: "Song", Artists: []string{"Artist"}, Isrc: "ISRC"}
+		withoutIsrc := export.Track{Uri: "spotify:track:b", Name: "Other", Artists: []string{"Artist"}, DurationMs: 200000}
+		report := &Report{Market: api.MarketFromToken, Items: []Item{
+			{Position: 0, Status: StatusUnavailable, Track: withIsrc},
+			{Position: 1, Status: StatusUnavailable, Track: withoutIsrc},
+		}}
+		release := func(uri string, name string, isPlayable bool) api.Track {
+			return api.Track{Uri: uri, Name: name, Artists: []api.SimplifiedArtist{{Name: "Artist"}}, DurationMs: 200000, IsPlayable: playable(isPlayable)}
+		}
+		client := &mockClient{results: map[string][]api.Track{
+			"isrc:ISRC": {
+				{Uri: "spotify:track:a"},
+				release("spotify:track:a2", "Song", true),
+			},
+			`track:"Other" artist:"Artist"`: {
+				release("spotify:track:b2", "Other", false),
+				release("spotify:track:b3", "Another", true),
+			},
+		}}
+		client.results["isrc:ISRC"][1].ExternalIds.Isrc = "ISRC"
+
+		// When looking up the alternatives
+		err := FindAlternatives(client, report)
+
+		// Then the playable release sharing the ISRC should be found
+		if err != nil || report.Items[0].Alternative == nil || report.Items[0].Alternative.Uri != "spotify:track:a2" {
+			t.Fatalf("Unexpected alternative %+v, %v", report.Items[0].Alternative, err)
+		}
+
+		// and no alternative to the other, the playable result being a different song
+		if report.Items[1].Alternative != nil {
+			t.Errorf("Unexpected alternative %+v", report.Items[1].Alternative)
+		}
+	})
+}
+
+func TestSwap(t *testing.T) {
+	t.Run("it should replace the unavailable tracks from the last one", func(t *testing.T) {
+		// Given a report with two alternatives
+		report := &Report{Playlist: export.Playlist{Id: "p1", SnapshotId: "s1"}, Items: []Item{
+			{Position: 2, Status: StatusUnavailable, Track: export.Track{Uri: "spotify:track:a"}, Alternative: &export.Track{Uri: "spotify:track:a2"}},
+			{Position: 4, Status: StatusRelinked, Track: export.Track{Uri: "spotify:track:r"}},
+			{Position: 7, Status: StatusUnavailable, Track: export.Track{Uri: "spotify:track:b"}, Alternative: &export.Track{Uri: "spotify:track:b2"}},
+		}}
+		client := &mockClient{}
+
+		// When swapping them
+		swapped, err := Swap(client, report)
+
+		// Then each one should be removed then inserted at its position
+		expected := []string{
+			"remove spotify:track:b@7 from s1", "insert spotify:track:b2@7",
+			"remove spotify:track:a@2 from inserted", "insert spotify:track:a2@2",
+		}
+		if err != nil || swapped != 2 || !reflect.DeepEqual(client.calls, expected) {
+			t.Errorf("Expected %v, got %v (%d, %v)", expected, client.calls, swapped, err)
+		}
+	})
+}
+
+func TestPrintReport(t *testing.T) {
+	t.Run("it should print the items and the totals", func(t *testing.T) {
+		alternative := export.Track{Uri: "spotify:track:a2", Name: "Song", Artists: []string{"Artist"}}
+		report := &Report{Playlist: export.Playlist{Name: "Mix"}, Checked: 10, Items: []Item{
+			{Position: 0, Status: StatusUnavailable, Reason: "market", Track: export.Track{Name: "Song", Artists: []string{"Artist"}}, Alternative: &alternative},
+			{Position: 3, Status: StatusRelinked, LinkedFrom: "spotify:track:old", Track: export.Track{Name: "Other", Artists: []string{"Artist"}}},
+		}}
+
+		buffer := &bytes.Buffer{}
+		PrintReport(buffer, report)
+
+		expected := strings.Join([]string{
+			"Mix #1 Artist - Song: unavailable (market), alternative Artist - Song spotify:track:a2",
+			"Mix #4 Artist - Other: relinked from spotify:track:old",
+			"Mix: 10 checked, 1 unavailable, 1 relinked",
+		}, "\n") + "\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+}
+
+// Helpers
+func playable(value bool) *bool {
+	return &value
+}
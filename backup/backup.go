@@ -0,0 +1,195 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/library"
+)
+
+type Library interface {
+	export.PlaylistSource
+	SavedTracks(handle func(export.Track) error) error
+	SavedAlbums(handle func(api.SavedAlbum) error) error
+	FollowedArtists(handle func(api.Artist) error) error
+}
+
+// Create snapshots the whole library into a new archive directory below root,
+// named after the snapshot time, and returns its path. It fails when the
+// archive already exists, and removes the archive when the snapshot fails.
+func Create(source Library, root string, now time.Time) (string, *Manifest, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", nil, fmt.Errorf("failed to create backup directory %s: %w", root, err)
+	}
+
+	archive := filepath.Join(root, now.UTC().Format("20060102T150405Z"))
+	if err := os.Mkdir(archive, 0o755); err != nil {
+		return "", nil, fmt.Errorf("failed to create archive %s: %w", archive, err)
+	}
+
+	manifest, err := create(source, archive, now)
+	if err != nil {
+		os.RemoveAll(archive)
+		return "", nil, err
+	}
+
+	return archive, manifest, nil
+}
+
+func create(source Library, archive string, now time.Time) (*Manifest, error) {
+	if err := os.Mkdir(filepath.Join(archive, "playlists"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive %s: %w", archive, err)
+	}
+
+	manifest := &Manifest{Version: ManifestVersion, SnapshotAt: now.UTC()}
+	add := func(file File, err error) error {
+		if err == nil {
+			manifest.Files = append(manifest.Files, file)
+		}
+		return err
+	}
+
+	playlists, err := source.Playlists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	jsonFormat, _ := export.ParseFormat("json")
+	for _, playlist := range playlists {
+		path := filepath.Join("playlists", export.FileName(playlist, jsonFormat))
+		file, err := writeFile(archive, path, KindPlaylist, func(w io.Writer) (int, error) {
+			return writePlaylist(w, playlist, func(handle func(export.Track) error) error {
+				return source.Tracks(playlist, handle)
+			})
+		})
+		file.PlaylistId = playlist.Id
+		file.SnapshotId = playlist.SnapshotId
+
+		if err := add(file, err); err != nil {
+			return nil, err
+		}
+	}
+
+	err = add(writeFile(archive, "saved_tracks.json", KindSavedTracks, func(w io.Writer) (int, error) {
+		return writePlaylist(w, library.LikedSongs, source.SavedTracks)
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = add(writeFile(archive, "saved_albums.json", KindSavedAlbums, func(w io.Writer) (int, error) {
+		return writeArray(w, source.SavedAlbums)
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = add(writeFile(archive, "followed_artists.json", KindFollowedArtists, func(w io.Writer) (int, error) {
+		return writeArray(w, source.FollowedArtists)
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeManifest(archive, manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// writeFile creates the file at path in the archive, computing its checksum
+// and size while it is written
+func writeFile(archive string, path string, kind string, write func(io.Writer) (int, error)) (File, error) {
+	out, err := os.Create(filepath.Join(archive, path))
+	if err != nil {
+		return File{}, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	items, err := write(io.MultiWriter(out, hash, counter))
+
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return File{}, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return File{
+		Path:   filepath.ToSlash(path),
+		Kind:   kind,
+		Sha256: hex.EncodeToString(hash.Sum(nil)),
+		Size:   counter.size,
+		Items:  items,
+	}, nil
+}
+
+func writePlaylist(w io.Writer, playlist export.Playlist, tracks func(func(export.Track) error) error) (int, error) {
+	writer := export.NewJsonWriter(w)
+	if err := writer.BeginPlaylist(playlist); err != nil {
+		return 0, err
+	}
+
+	items := 0
+	err := tracks(func(track export.Track) error {
+		items++
+		return writer.WriteTrack(track)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writer.EndPlaylist(); err != nil {
+		return 0, err
+	}
+
+	return items, writer.Close()
+}
+
+// writeArray streams the items to a json array
+func writeArray[T any](w io.Writer, stream func(func(T) error) error) (int, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	items := 0
+	err := stream(func(item T) error {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+
+		if items > 0 {
+			encoded = append([]byte{','}, encoded...)
+		}
+		items++
+
+		_, err = w.Write(encoded)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return items, err
+}
+
+type countingWriter struct {
+	size int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	return len(p), nil
+}
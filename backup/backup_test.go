@@ -0,0 +1,187 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Library
+type mockLibrary struct {
+	playlists []export.Playlist
+	tracks    map[string][]export.Track
+	saved     []export.Track
+	albums    []api.SavedAlbum
+	artists   []api.Artist
+
+	tracksError error
+}
+
+func (m *mockLibrary) Playlists() ([]export.Playlist, error) {
+	return m.playlists, nil
+}
+
+func (m *mockLibrary) Tracks(playlist export.Playlist, handle func(export.Track) error) error {
+	if m.tracksError != nil {
+		return m.tracksError
+	}
+	return stream(m.tracks[playlist.Id], handle)
+}
+
+func (m *mockLibrary) SavedTracks(handle func(export.Track) error) error {
+	return stream(m.saved, handle)
+}
+
+func (m *mockLibrary) SavedAlbums(handle func(api.SavedAlbum) error) error {
+	return stream(m.albums, handle)
+}
+
+func (m *mockLibrary) FollowedArtists(handle func(api.Artist) error) error {
+	return stream(m.artists, handle)
+}
+
+func stream[T any](items []T, handle func(T) error) error {
+	for _, item := range items {
+		if err := handle(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	t.Run("it should snapshot the library in a timestamped archive", func(t *testing.T) {
+		// Given a library
+		source := createLibrary()
+		root := t.TempDir()
+
+		// When creating a backup
+		archive, manifest, err := Create(source, root, now)
+
+		// Then the archive should be named after the snapshot time
+		if err != nil {
+			t.Fatalf("Create returned an error: %s", err.Error())
+		}
+		if archive != filepath.Join(root, "20240304T050607Z") {
+			t.Errorf("Unexpected archive path %s", archive)
+		}
+
+		// and the manifest should list every file
+		if !manifest.SnapshotAt.Equal(now) || len(manifest.Files) != 4 {
+			t.Fatalf("Unexpected manifest %+v", manifest)
+		}
+		playlist := manifest.Files[0]
+		if playlist.Kind != KindPlaylist || playlist.PlaylistId != "p1" || playlist.SnapshotId != "s1" || playlist.Items != 2 {
+			t.Errorf("Unexpected playlist entry %+v", playlist)
+		}
+		expectedItems := map[string]int{KindSavedTracks: 1, KindSavedAlbums: 1, KindFollowedArtists: 2}
+		for _, file := range manifest.Files[1:] {
+			if file.Items != expectedItems[file.Kind] {
+				t.Errorf("Expected %d items in %s, got %d", expectedItems[file.Kind], file.Path, file.Items)
+			}
+		}
+
+		// and the manifest should be written along the files
+		written, err := ReadManifest(archive)
+		if err != nil || len(written.Files) != 4 {
+			t.Fatalf("Expected the manifest to be written: %v", err)
+		}
+
+		// and the playlist file should be a json export
+		var document export.Document
+		content, _ := os.ReadFile(filepath.Join(archive, playlist.Path))
+		if err := json.Unmarshal(content, &document); err != nil || len(document.Playlists[0].Tracks) != 2 {
+			t.Errorf("Expected a json export for the playlist, got %s", content)
+		}
+
+		// and the artists should be a json array
+		var artists []api.Artist
+		content, _ = os.ReadFile(filepath.Join(archive, "followed_artists.json"))
+		if err := json.Unmarshal(content, &artists); err != nil || len(artists) != 2 || artists[1].Genres[0] != "jazz" {
+			t.Errorf("Expected the followed artists, got %s", content)
+		}
+
+		// and the checksums should match
+		if err := Verify(archive); err != nil {
+			t.Errorf("Verify returned an error: %s", err.Error())
+		}
+	})
+
+	t.Run("it should return the error of the library", func(t *testing.T) {
+		source := createLibrary()
+		source.tracksError = errors.New("mock error")
+
+		root := t.TempDir()
+
+		_, _, err := Create(source, root, now)
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+
+		// and the partial archive should be removed
+		if entries, _ := os.ReadDir(root); len(entries) != 0 {
+			t.Errorf("Expected the archive to be removed, got %v", entries)
+		}
+	})
+
+	t.Run("it should not write into an existing archive", func(t *testing.T) {
+		// Given an archive taken at the same second
+		root := t.TempDir()
+		if _, _, err := Create(createLibrary(), root, now); err != nil {
+			t.Fatalf("Create returned an error: %s", err.Error())
+		}
+
+		// When taking another one
+		_, _, err := Create(createLibrary(), root, now)
+
+		// Then it should fail
+		if !errors.Is(err, fs.ErrExist) {
+			t.Errorf("Expected an already existing archive, got %v", err)
+		}
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("it should detect tampered files", func(t *testing.T) {
+		// Given an archive
+		archive, _, err := Create(createLibrary(), t.TempDir(), time.Now())
+		if err != nil {
+			t.Fatalf("Create returned an error: %s", err.Error())
+		}
+
+		// When a file is modified
+		os.WriteFile(filepath.Join(archive, "saved_albums.json"), []byte("[]"), 0o644)
+
+		// Then the verification should fail
+		err = Verify(archive)
+		if err == nil || err.Error() != "checksum mismatch for saved_albums.json" {
+			t.Errorf("Expected a checksum mismatch, got %v", err)
+		}
+	})
+}
+
+// Helpers
+func createLibrary() *mockLibrary {
+	jazz := api.Artist{Genres: []string{"jazz"}}
+	jazz.Id = "a2"
+
+	return &mockLibrary{
+		playlists: []export.Playlist{{Id: "p1", Name: "Playlist", SnapshotId: "s1"}},
+		tracks: map[string][]export.Track{
+			"p1": {{Id: "t1", Name: "Song 1"}, {Id: "t2", Name: "Song 2"}},
+		},
+		saved:   []export.Track{{Id: "t3"}},
+		albums:  []api.SavedAlbum{{}},
+		artists: []api.Artist{{}, jazz},
+	}
+}
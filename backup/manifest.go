@@ -0,0 +1,103 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ManifestFile    = "manifest.json"
+	ManifestVersion = 1
+)
+
+const (
+	KindPlaylist        = "playlist"
+	KindSavedTracks     = "saved_tracks"
+	KindSavedAlbums     = "saved_albums"
+	KindFollowedArtists = "followed_artists"
+)
+
+// Manifest describes the content of an archive, playlists and saved tracks
+// are stored as json exports while albums and artists are stored as arrays
+// of Web API objects
+type Manifest struct {
+	Version    int       `json:"version"`
+	SnapshotAt time.Time `json:"snapshot_at"`
+	Files      []File    `json:"files"`
+}
+
+type File struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	Items  int    `json:"items"`
+	// Set for playlists only
+	PlaylistId string `json:"playlist_id,omitempty"`
+	SnapshotId string `json:"snapshot_id,omitempty"`
+}
+
+func ReadManifest(archive string) (*Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(archive, ManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// Verify checks that every file of the archive matches its checksum
+func Verify(archive string) error {
+	manifest, err := ReadManifest(archive)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range manifest.Files {
+		checksum, err := checksumOf(filepath.Join(archive, file.Path))
+		if err != nil {
+			return err
+		}
+
+		if checksum != file.Sha256 {
+			return fmt.Errorf("checksum mismatch for %s", file.Path)
+		}
+	}
+
+	return nil
+}
+
+func checksumOf(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func writeManifest(archive string, manifest *Manifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(archive, ManifestFile), append(content, '\n'), 0o644)
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/client/auth"
+	"prisco.dev/spotify-playlist/client/auth/callback"
+	"prisco.dev/spotify-playlist/client/auth/tokenclient"
+)
+
+var ErrLoginFailed = errors.New("login failed")
+
+func loginCommand() *Command {
+	var clientId, redirectUrl, scopes string
+	var port int
+
+	return &Command{
+		Name:    "login",
+		Usage:   "[flags]",
+		Summary: "Authorize the access to your Spotify account",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&clientId, "client-id", "", "`id` of the Spotify application, client_id of the config by default")
+			flags.StringVar(&redirectUrl, "redirect-url", "", "redirect `url` registered for the application, redirect_url of the config by default")
+			flags.StringVar(&scopes, "scopes", "", "comma separated `scopes` to request, scopes of the config by default")
+			flags.IntVar(&port, "callback-port", 0, "`port` of the local callback server, callback_port of the config by default")
+		},
+		Run: func(app *App, args []string) error {
+			settings, err := app.Configuration()
+			if err != nil {
+				return err
+			}
+			for name, value := range map[string]string{"client_id": clientId, "redirect_url": redirectUrl, "scopes": scopes} {
+				if value != "" {
+					settings.Set(name, value)
+				}
+			}
+			if port != 0 {
+				if err := settings.Set("callback_port", strconv.Itoa(port)); err != nil {
+					return usageErrorf("login", "%s", err.Error())
+				}
+			}
+
+			if settings.ClientId == "" {
+				return usageErrorf("login", "a client id is required, set it with 'config set client_id <id>' or --client-id")
+			}
+			clientId, redirectUrl = settings.ClientId, settings.Redirect()
+			listenPort, err := settings.ListenPort()
+			if err != nil {
+				return usageErrorf("login", "%s", err.Error())
+			}
+			if port != 0 && port != listenPort {
+				return usageErrorf("login", "callback port %d does not match the port %d of the redirect url %s", port, listenPort, redirectUrl)
+			}
+
+			store := &auth.Store{}
+			authenticator := auth.NewAuthenticator(
+				clientId,
+				redirectUrl,
+				settings.Scopes,
+				auth.BrowserCommandExecutor{},
+				&auth.RandomPkceGenerator{},
+				callback.HandleCallbackOn(fmt.Sprintf(":%d", listenPort)),
+				store,
+			)
+
+			fmt.Fprintf(app.progress(), "Waiting for the authorization in the browser...\n")
+			if err := authenticator.Authenticate(); err != nil {
+				return fmt.Errorf("%w: %s", ErrLoginFailed, err.Error())
+			}
+
+			tokenClient := tokenclient.NewSpotifyTokenClient(&http.Client{Transport: app.transport}, clientId, redirectUrl)
+			token, err := tokenClient.Exchange(store.Code, store.Verifier)
+			if err != nil {
+				return fmt.Errorf("%w: failed to get the token: %s", ErrLoginFailed, err.Error())
+			}
+			user, err := api.NewClient(&http.Client{Transport: app.transport}, token.AccessToken).CurrentUser()
+			if err != nil {
+				return fmt.Errorf("%w: failed to get the account: %s", ErrLoginFailed, err.Error())
+			}
+
+			// The profile may have been logged in with another account
+			if err := app.clearCache(app.loadPreviousStore()); err != nil {
+				return err
+			}
+
+			store.ClientId = clientId
+			store.UserId = user.Id
+			if err := app.saveToken(store, token); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(app.stdout, "Logged in with profile %s\n", app.Profile)
+			return nil
+		},
+	}
+}
+
+func logoutCommand() *Command {
+	return &Command{
+		Name:    "logout",
+		Summary: "Remove the saved credentials of the profile",
+		Run: func(app *App, args []string) error {
+			store := app.loadPreviousStore()
+			err := os.Remove(app.credentialsPath())
+			if errors.Is(err, fs.ErrNotExist) {
+				fmt.Fprintf(app.stdout, "Profile %s is not logged in\n", app.Profile)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to remove the credentials: %w", err)
+			}
+			if err := app.clearCache(store); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(app.stdout, "Logged out of profile %s\n", app.Profile)
+			return nil
+		},
+	}
+}
+
+// Account is the output of whoami
+type Account struct {
+	Id          string    `json:"id"`
+	DisplayName string    `json:"display_name"`
+	Country     string    `json:"country"`
+	Product     string    `json:"product"`
+	Profile     string    `json:"profile"`
+	Scopes      []string  `json:"scopes"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func whoamiCommand() *Command {
+	return &Command{
+		Name:    "whoami",
+		Summary: "Show the account of the profile, its granted scopes and the expiry of its token",
+		Run: func(app *App, args []string) error {
+			output, err := app.output("whoami", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+			store, err := auth.LoadStore(app.credentialsPath())
+			if err != nil {
+				return err
+			}
+
+			user, err := client.CurrentUser()
+			if err != nil {
+				return err
+			}
+
+			account := Account{
+				Id:          user.Id,
+				DisplayName: user.DisplayName,
+				Country:     user.Country,
+				Product:     user.Product,
+				Profile:     app.Profile,
+				Scopes:      store.Scopes,
+				ExpiresAt:   store.ExpiresAt,
+			}
+			if output == OutputJson {
+				return writeJson(app.stdout, account)
+			}
+
+			return printAccount(app, account)
+		},
+	}
+}
+
+func printAccount(app *App, account Account) error {
+	expiry := "unknown"
+	if !account.ExpiresAt.IsZero() {
+		expiry = fmt.Sprintf(
+			"%s (in %s)",
+			account.ExpiresAt.Local().Format(time.DateTime),
+			account.ExpiresAt.Sub(app.now()).Round(time.Minute),
+		)
+	}
+	scopes := "unknown"
+	if len(account.Scopes) > 0 {
+		scopes = strings.Join(account.Scopes, ", ")
+	}
+
+	rows := [][]string{
+		{"Name", account.DisplayName},
+		{"Id", account.Id},
+		{"Country", account.Country},
+		{"Product", account.Product},
+		{"Profile", account.Profile},
+		{"Scopes", scopes},
+		{"Token expires", expiry},
+	}
+
+	table := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(table, "%s:\t%s\n", row[0], row[1])
+	}
+	return table.Flush()
+}
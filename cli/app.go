@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/client/auth"
+	"prisco.dev/spotify-playlist/client/auth/tokenclient"
+	"prisco.dev/spotify-playlist/config"
+	"prisco.dev/spotify-playlist/library"
+)
+
+// Name is the name of the executable and of its configuration directories
+const Name = "spotify-playlist"
+
+// Exit codes, by class of error
+const (
+	ExitOk    = 0
+	ExitError = 1
+	ExitUsage = 2
+	ExitAuth  = 3
+	ExitApi   = 4
+)
+
+const (
+	OutputText     = "text"
+	OutputJson     = "json"
+	OutputMarkdown = "markdown"
+	OutputTable    = "table"
+	OutputCsv      = "csv"
+	OutputHtml     = "html"
+)
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+var (
+	ErrNotLoggedIn  = errors.New("not logged in, run 'spotify-playlist login' first")
+	ErrTokenExpired = errors.New("the token expired, run 'spotify-playlist login' again")
+)
+
+// Globals are the flags accepted by every command
+type Globals struct {
+	Profile string
+	Config  string
+	Output  string
+	// Verbosity is 0 for errors only, 1 for progress and 2 for debug output
+	Verbosity int
+	Color     string
+}
+
+func defaultGlobals() Globals {
+	config := ""
+	if directory, err := os.UserConfigDir(); err == nil {
+		config = filepath.Join(directory, Name, "config.json")
+	}
+
+	return Globals{
+		Profile:   "default",
+		Config:    config,
+		Output:    OutputText,
+		Verbosity: 1,
+		Color:     ColorAuto,
+	}
+}
+
+// register binds the flags to the globals, using their current values as
+// defaults so that they can be parsed at every level of the command tree
+func (g *Globals) register(flags *flag.FlagSet) {
+	flags.StringVar(&g.Profile, "profile", g.Profile, "name of the account `profile` to use")
+	flags.StringVar(&g.Config, "config", g.Config, "`path` of the configuration file, credentials are stored next to it")
+	flags.StringVar(&g.Output, "output", g.Output, "output `format` of the command: text, json, markdown, table, csv or html")
+	flags.IntVar(&g.Verbosity, "verbosity", g.Verbosity, "`level` of the messages: 0 errors only, 1 progress, 2 debug")
+	flags.StringVar(&g.Color, "color", g.Color, "colorize the messages: auto, always or never")
+}
+
+func (g *Globals) validate(command string) error {
+	switch {
+	case g.Color != ColorAuto && g.Color != ColorAlways && g.Color != ColorNever:
+		return usageErrorf(command, "invalid color %q, expected auto, always or never", g.Color)
+	case g.Verbosity < 0 || g.Verbosity > 2:
+		return usageErrorf(command, "invalid verbosity %d, expected 0, 1 or 2", g.Verbosity)
+	case g.Profile == "" || strings.ContainsAny(g.Profile, `/\`):
+		return usageErrorf(command, "invalid profile %q", g.Profile)
+	}
+
+	return nil
+}
+
+// App holds the state shared by the commands of an invocation
+type App struct {
+	Globals
+	stdout    io.Writer
+	stderr    io.Writer
+	transport http.RoundTripper
+	now       func() time.Time
+	config    *config.Config
+	client    *api.Client
+}
+
+func newApp(stdout io.Writer, stderr io.Writer) *App {
+	return &App{
+		Globals:   defaultGlobals(),
+		stdout:    stdout,
+		stderr:    stderr,
+		transport: http.DefaultTransport,
+		now:       time.Now,
+	}
+}
+
+// Run executes the command line and returns the exit code
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	return newApp(stdout, stderr).run(args)
+}
+
+func (a *App) run(args []string) int {
+	root := rootCommand()
+
+	err := root.execute(a, []string{root.Name}, args)
+	if err == nil {
+		return ExitOk
+	}
+
+	fmt.Fprintf(a.stderr, "%s %s\n", a.paint(a.stderr, red, "error:"), err.Error())
+
+	var usage *UsageError
+	if errors.As(err, &usage) {
+		fmt.Fprintf(a.stderr, "Run '%s' for usage.\n", strings.TrimSpace(Name+" help "+usage.Command))
+	}
+
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var usage *UsageError
+	var apiError *api.Error
+
+	switch {
+	case errors.As(err, &usage):
+		return ExitUsage
+	case errors.Is(err, ErrNotLoggedIn), errors.Is(err, ErrTokenExpired), errors.Is(err, ErrLoginFailed):
+		return ExitAuth
+	case errors.As(err, &apiError) && (apiError.Status == http.StatusUnauthorized || apiError.Status == http.StatusForbidden):
+		return ExitAuth
+	case errors.As(err, &apiError):
+		return ExitApi
+	default:
+		return ExitError
+	}
+}
+
+// output returns the output format, which must be one of the supported ones
+func (a *App) output(command string, supported ...string) (string, error) {
+	for _, output := range supported {
+		if a.Output == output {
+			return output, nil
+		}
+	}
+
+	return "", usageErrorf(command, "unsupported output %q, expected one of %v", a.Output, supported)
+}
+
+// progress receives the progress messages, unless the verbosity is 0
+func (a *App) progress() io.Writer {
+	if a.Verbosity < 1 {
+		return io.Discard
+	}
+
+	return a.stderr
+}
+
+func (a *App) configDirectory() string {
+	return filepath.Dir(a.Config)
+}
+
+func (a *App) credentialsPath() string {
+	return filepath.Join(a.configDirectory(), "credentials", a.Profile+".json")
+}
+
+func (a *App) cacheDirectory() string {
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		directory = a.configDirectory()
+	}
+
+	return filepath.Join(directory, Name)
+}
+
+// responsesDirectory holds the cached responses of the Web API
+func (a *App) responsesDirectory() string {
+	return filepath.Join(a.cacheDirectory(), "http")
+}
+
+// cacheUser keys the cached responses by the account of the credentials,
+// or by the profile for credentials saved before the account was
+func (a *App) cacheUser(store *auth.Store) string {
+	if store.UserId != "" {
+		return store.UserId
+	}
+
+	return a.Profile
+}
+
+// clearCache removes the cached responses of the account of the credentials
+func (a *App) clearCache(store *auth.Store) error {
+	_, err := api.ClearUserCache(a.responsesDirectory(), a.cacheUser(store))
+	return err
+}
+
+// loadPreviousStore returns the saved credentials of the profile, empty when
+// there are none or they cannot be read
+func (a *App) loadPreviousStore() *auth.Store {
+	store, err := auth.LoadStore(a.credentialsPath())
+	if err != nil {
+		return &auth.Store{}
+	}
+
+	return store
+}
+
+// Configuration returns the configuration file overridden by the environment
+func (a *App) Configuration() (*config.Config, error) {
+	if a.config != nil {
+		return a.config, nil
+	}
+
+	loaded, err := config.Load(a.Config, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	a.config = loaded
+	return a.config, nil
+}
+
+// Client returns the Web API client of the profile, responses are cached on
+// disk when enabled and logged in debug mode
+func (a *App) Client() (*api.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	settings, err := a.Configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := auth.LoadStore(a.credentialsPath())
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && store.Token == "") {
+		return nil, ErrNotLoggedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	if store.Expired(a.now()) {
+		if err := a.refresh(store); err != nil {
+			return nil, err
+		}
+	}
+
+	transport := a.transport
+	if a.Verbosity >= 2 {
+		transport = &logTransport{next: transport, writer: a.stderr}
+	}
+	transport = &limitTransport{next: transport, slots: make(chan struct{}, settings.Concurrency)}
+	if settings.Cache.Enabled {
+		transport = api.NewCache(
+			transport,
+			a.responsesDirectory(),
+			a.cacheUser(store),
+			time.Duration(settings.Cache.Ttl),
+			int64(settings.Cache.MaxSizeMb)<<20,
+		)
+	}
+
+	a.client = api.NewClient(&http.Client{Transport: transport}, store.Token)
+	return a.client, nil
+}
+
+// refresh replaces the expired token of the store with a new one, obtained
+// with the refresh token saved at login
+func (a *App) refresh(store *auth.Store) error {
+	if store.RefreshToken == "" || store.ClientId == "" {
+		return ErrTokenExpired
+	}
+
+	tokenClient := tokenclient.NewSpotifyTokenClient(&http.Client{Transport: a.transport}, store.ClientId, "")
+	token, err := tokenClient.Refresh(store.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("%w: failed to refresh the token: %s", ErrTokenExpired, err.Error())
+	}
+
+	return a.saveToken(store, token)
+}
+
+// saveToken saves a new token in the credentials of the profile, keeping the
+// refresh token and the scopes when the token endpoint does not return them
+func (a *App) saveToken(store *auth.Store, token *tokenclient.Token) error {
+	store.Token = token.AccessToken
+	if token.RefreshToken != "" {
+		store.RefreshToken = token.RefreshToken
+	}
+	if len(token.Scopes) > 0 {
+		store.Scopes = token.Scopes
+	}
+	store.ExpiresAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		store.ExpiresAt = a.now().Add(token.ExpiresIn).UTC()
+	}
+
+	return store.Save(a.credentialsPath())
+}
+
+// Source returns the library of the profile
+func (a *App) Source() (*library.Source, error) {
+	client, err := a.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return library.NewSource(client), nil
+}
+
+// logTransport logs the requests reaching the network
+type logTransport struct {
+	next   http.RoundTripper
+	writer io.Writer
+}
+
+func (l *logTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	start := time.Now()
+	response, err := l.next.RoundTrip(request)
+	if err != nil {
+		fmt.Fprintf(l.writer, "%s %s: %s\n", request.Method, request.URL, err.Error())
+		return nil, err
+	}
+
+	fmt.Fprintf(l.writer, "%s %s: %d in %s\n", request.Method, request.URL, response.StatusCode, time.Since(start).Round(time.Millisecond))
+	return response, nil
+}
+
+// limitTransport bounds the number of concurrent requests
+type limitTransport struct {
+	next  http.RoundTripper
+	slots chan struct{}
+}
+
+func (l *limitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	l.slots <- struct{}{}
+	defer func() { <-l.slots }()
+
+	return l.next.RoundTrip(request)
+}
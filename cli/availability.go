@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"prisco.dev/spotify-playlist/availability"
+	"prisco.dev/spotify-playlist/client/api"
+)
+
+func availabilityCommand() *Command {
+	var market string
+	var alternatives, swap bool
+
+	return &Command{
+		Name:    "availability",
+		Usage:   "[flags] [playlist...]",
+		Summary: "Report the unavailable and relinked tracks of playlists, all of them when none is given",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&market, "market", api.MarketFromToken, "`market` to check, a country code or from_token for the one of the account")
+			flags.BoolVar(&alternatives, "alternatives", false, "look up a playable release of the unavailable tracks")
+			flags.BoolVar(&swap, "swap", false, "replace the unavailable tracks by their alternative, implies --alternatives")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("availability", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+			playlists, err := selectPlaylists(source, args).Playlists()
+			if err != nil {
+				return err
+			}
+
+			reports := make([]*availability.Report, 0, len(playlists))
+			for _, playlist := range playlists {
+				report, err := availability.Check(client, playlist, market)
+				if err != nil {
+					return err
+				}
+				if alternatives || swap {
+					if err := availability.FindAlternatives(client, report); err != nil {
+						return err
+					}
+				}
+				reports = append(reports, report)
+
+				if output == OutputText {
+					if err := availability.PrintReport(app.stdout, report); err != nil {
+						return err
+					}
+				}
+				if !swap {
+					continue
+				}
+
+				swapped, err := availability.Swap(client, report)
+				if err != nil {
+					return err
+				}
+				if swapped > 0 {
+					fmt.Fprintf(app.progress(), "Swapped %d tracks of %s\n", swapped, playlist.Name)
+				}
+			}
+
+			if output == OutputJson {
+				return writeJson(app.stdout, reports)
+			}
+			return nil
+		},
+	}
+}
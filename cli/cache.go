@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+)
+
+func cacheCommand() *Command {
+	return &Command{
+		Name:    "cache",
+		Usage:   "<command>",
+		Summary: "Inspect or clear the local cache of the Web API responses",
+		Commands: []*Command{
+			{
+				Name:    "stats",
+				Summary: "Show the number and size of the cached responses",
+				Run: func(app *App, args []string) error {
+					output, err := app.output("cache stats", OutputText, OutputJson)
+					if err != nil {
+						return err
+					}
+
+					stats, err := api.ReadCacheStats(app.responsesDirectory())
+					if err != nil {
+						return err
+					}
+
+					if output == OutputJson {
+						return writeJson(app.stdout, stats)
+					}
+
+					fmt.Fprintf(app.stdout, "Directory: %s\n", app.cacheDirectory())
+					fmt.Fprintf(app.stdout, "Responses: %d\n", stats.Entries)
+					fmt.Fprintf(app.stdout, "Size: %.1f MiB\n", float64(stats.Size)/(1<<20))
+					if stats.Entries > 0 {
+						fmt.Fprintf(app.stdout, "Last used: %s to %s\n", stats.Oldest.Format(time.DateTime), stats.Newest.Format(time.DateTime))
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "clear",
+				Summary: "Remove the cached responses and audio features",
+				Run: func(app *App, args []string) error {
+					removed, err := api.ClearCache(app.responsesDirectory())
+					if err != nil {
+						return err
+					}
+
+					err = os.Remove(filepath.Join(app.cacheDirectory(), "audio_features.json"))
+					if err != nil && !errors.Is(err, fs.ErrNotExist) {
+						return fmt.Errorf("failed to remove the audio features: %w", err)
+					}
+
+					_, err = fmt.Fprintf(app.stdout, "Removed %d responses\n", removed)
+					return err
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,582 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/client/auth"
+)
+
+// To mock the Web API, we mock the underlying roundtripper
+type mockRoundTripper struct {
+	roundTripFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m.roundTripFunc(req)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("it should list the commands in the help", func(t *testing.T) {
+		app, stdout, _ := createApp(t, nil)
+
+		code := app.run([]string{"help"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "export") || !strings.Contains(stdout.String(), "-profile") {
+			t.Errorf("Unexpected help (%d):\n%s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should print the help of a command", func(t *testing.T) {
+		app, stdout, _ := createApp(t, nil)
+
+		code := app.run([]string{"export", "-h"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "Usage: spotify-playlist export") || !strings.Contains(stdout.String(), "-format") {
+			t.Errorf("Unexpected help (%d):\n%s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should return a usage error for unknown commands", func(t *testing.T) {
+		app, _, stderr := createApp(t, nil)
+
+		if code := app.run([]string{"unknown"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+		if !strings.Contains(stderr.String(), `unknown command "unknown"`) {
+			t.Errorf("Unexpected error output %s", stderr.String())
+		}
+	})
+
+	t.Run("it should return a usage error for invalid flags", func(t *testing.T) {
+		app, _, _ := createApp(t, nil)
+
+		if code := app.run([]string{"playlists", "--color", "pink"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+
+	t.Run("it should reject a callback port not matching the redirect url", func(t *testing.T) {
+		app, _, stderr := createApp(t, nil)
+
+		code := app.run([]string{"login", "--client-id", "id", "--redirect-url", "http://127.0.0.1:9000/callback", "--callback-port", "8080"})
+
+		if code != ExitUsage || !strings.Contains(stderr.String(), "does not match the port 9000") {
+			t.Errorf("Expected a usage error, got %d: %s", code, stderr.String())
+		}
+	})
+
+	t.Run("it should return an auth error when not logged in", func(t *testing.T) {
+		app, _, _ := createApp(t, nil)
+
+		if code := app.run([]string{"whoami"}); code != ExitAuth {
+			t.Errorf("Expected exit code %d, got %d", ExitAuth, code)
+		}
+	})
+
+	t.Run("it should return an auth error when the token is rejected", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+		app.transport = &mockRoundTripper{func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusUnauthorized, `{"error": {"status": 401, "message": "The access token expired"}}`), nil
+		}}
+
+		if code := app.run([]string{"whoami"}); code != ExitAuth {
+			t.Errorf("Expected exit code %d, got %d", ExitAuth, code)
+		}
+	})
+
+	t.Run("it should return an api error for failing requests", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+		app.transport = &mockRoundTripper{func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusInternalServerError, ""), nil
+		}}
+
+		if code := app.run([]string{"whoami"}); code != ExitApi {
+			t.Errorf("Expected exit code %d, got %d", ExitApi, code)
+		}
+	})
+
+	t.Run("it should parse the global flags after the command", func(t *testing.T) {
+		// Given a logged in profile
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me": `{"id": "user", "display_name": "User"}`,
+		})
+
+		// When asking for json output after the command
+		code := app.run([]string{"whoami", "--output", "json"})
+
+		// Then the user should be printed as json
+		var user map[string]any
+		if code != ExitOk || json.Unmarshal(stdout.Bytes(), &user) != nil || user["id"] != "user" {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+}
+
+func TestWhoamiCommand(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	routes := map[string]string{
+		"https://api.spotify.com/v1/me": `{"id": "user", "display_name": "User", "country": "IT", "product": "premium"}`,
+	}
+
+	t.Run("it should print the account with its scopes and token expiry", func(t *testing.T) {
+		// Given a profile logged in with two scopes
+		app, stdout, _ := createApp(t, routes)
+		app.now = func() time.Time { return now }
+		saveStore(t, app, &auth.Store{Token: "token", Scopes: []string{"a", "b"}, ExpiresAt: now.Add(45 * time.Minute)})
+
+		// When running whoami as json
+		code := app.run([]string{"whoami", "--output", "json"})
+
+		// Then the account should be printed with the credentials details
+		var account Account
+		if code != ExitOk || json.Unmarshal(stdout.Bytes(), &account) != nil {
+			t.Fatalf("Unexpected output (%d): %s", code, stdout.String())
+		}
+		expected := Account{"user", "User", "IT", "premium", "default", []string{"a", "b"}, now.Add(45 * time.Minute)}
+		if !reflect.DeepEqual(account, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, account)
+		}
+	})
+
+	t.Run("it should print the remaining lifetime of the token", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+		app.now = func() time.Time { return now }
+		saveStore(t, app, &auth.Store{Token: "token", ExpiresAt: now.Add(45 * time.Minute)})
+
+		app.run([]string{"whoami"})
+
+		if !strings.Contains(stdout.String(), "(in 45m0s)") || !strings.Contains(stdout.String(), "premium") {
+			t.Errorf("Unexpected output %s", stdout.String())
+		}
+	})
+
+	t.Run("it should return an auth error when the token expired", func(t *testing.T) {
+		app, _, _ := createApp(t, routes)
+		app.now = func() time.Time { return now }
+		saveStore(t, app, &auth.Store{Token: "token", ExpiresAt: now.Add(-time.Minute)})
+
+		if code := app.run([]string{"whoami"}); code != ExitAuth {
+			t.Errorf("Expected exit code %d, got %d", ExitAuth, code)
+		}
+	})
+
+	t.Run("it should refresh an expired token and save it", func(t *testing.T) {
+		// Given an expired token with a refresh token
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me":          routes["https://api.spotify.com/v1/me"],
+			"https://accounts.spotify.com/api/token": `{"access_token": "new-token", "expires_in": 3600}`,
+		})
+		app.now = func() time.Time { return now }
+		saveStore(t, app, &auth.Store{ClientId: "id", Token: "token", RefreshToken: "refresh", ExpiresAt: now.Add(-time.Minute)})
+
+		// When running whoami
+		code := app.run([]string{"whoami"})
+
+		// Then the account should be printed
+		if code != ExitOk || !strings.Contains(stdout.String(), "User") {
+			t.Errorf("Unexpected output (%d) %s", code, stdout.String())
+		}
+
+		// and the new token should be saved, keeping the refresh token
+		store, _ := auth.LoadStore(app.credentialsPath())
+		if store.Token != "new-token" || store.RefreshToken != "refresh" || !store.ExpiresAt.Equal(now.Add(time.Hour)) {
+			t.Errorf("Unexpected store %+v", store)
+		}
+	})
+}
+
+func TestLogoutCommand(t *testing.T) {
+	t.Run("it should remove the credentials and the cached responses of the account", func(t *testing.T) {
+		// Given responses cached for the account of the profile and for another one
+		app, _, _ := createApp(t, map[string]string{"https://api.spotify.com/v1/me": `{"id": "user"}`})
+		saveStore(t, app, &auth.Store{Token: "token", UserId: "user"})
+		app.run([]string{"whoami"})
+		other := api.NewCache(app.transport, app.responsesDirectory(), "other", time.Hour, 0)
+		(&http.Client{Transport: other}).Get("https://api.spotify.com/v1/me")
+
+		// When logging out
+		code := app.run([]string{"logout"})
+
+		// Then only the responses of the other account should be left
+		stats, _ := api.ReadCacheStats(app.responsesDirectory())
+		if code != ExitOk || stats.Entries != 1 {
+			t.Errorf("Expected the response of the other account only, got %d (%d)", stats.Entries, code)
+		}
+		if _, err := os.Stat(app.credentialsPath()); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Expected the credentials to be removed, got %v", err)
+		}
+	})
+}
+
+func TestPlaylistsCommand(t *testing.T) {
+	routes := map[string]string{
+		"https://api.spotify.com/v1/me": `{"id": "me"}`,
+		"https://api.spotify.com/v1/me/playlists?limit=50": `{"items": [
+			{"id": "p1", "name": "Rock classics", "owner": {"id": "me"}, "public": true, "snapshot_id": "s1", "tracks": {"total": 30}},
+			{"id": "p2", "name": "Rock ballads", "owner": {"id": "friend"}, "collaborative": true, "tracks": {"total": 50}},
+			{"id": "p3", "name": "Jazz", "owner": {"id": "me"}, "tracks": {"total": 5}}
+		]}`,
+	}
+
+	t.Run("it should list the playlists as an aligned table", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"playlists"})
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if code != ExitOk || len(lines) != 4 || !strings.HasPrefix(lines[0], "ID  NAME") {
+			t.Fatalf("Unexpected output (%d):\n%s", code, stdout.String())
+		}
+		if !strings.Contains(lines[1], "p1  Rock classics  me") || !strings.Contains(lines[1], "30      true") {
+			t.Errorf("Unexpected row %s", lines[1])
+		}
+	})
+
+	t.Run("it should combine the filters", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"playlists", "--owned", "--name-regex", "^Rock", "--output", "json"})
+
+		var playlists []map[string]any
+		if code != ExitOk || json.Unmarshal(stdout.Bytes(), &playlists) != nil || len(playlists) != 1 || playlists[0]["id"] != "p1" {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should write csv", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"playlists", "--min-tracks", "30", "--output", "csv"})
+
+		expected := "id,name,owner,tracks,public,collaborative,snapshot_id\n" +
+			"p1,Rock classics,me,30,true,false,s1\n" +
+			"p2,Rock ballads,friend,50,false,true,\n"
+		if code != ExitOk || stdout.String() != expected {
+			t.Errorf("Unexpected output (%d):\n%s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should return a usage error for invalid regexes", func(t *testing.T) {
+		app, _, _ := createApp(t, routes)
+
+		if code := app.run([]string{"playlists", "--name-regex", "("}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+func TestExportCommand(t *testing.T) {
+	t.Run("it should list the formats", func(t *testing.T) {
+		app, stdout, _ := createApp(t, nil)
+
+		code := app.run([]string{"export", "--format", "list"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "xspf") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should export the selected playlists", func(t *testing.T) {
+		// Given a library with two playlists
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me/playlists?limit=50":                                          `{"items": [{"id": "p1", "name": "First"}, {"id": "p2", "name": "Second"}]}`,
+			"https://api.spotify.com/v1/playlists/p2/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [{"track": {"id": "t1", "name": "Song"}}]}`,
+		})
+
+		// When exporting the second one as ndjson
+		code := app.run([]string{"export", "--format", "ndjson", "p2"})
+
+		// Then a single track should be exported
+		if code != ExitOk || strings.Count(stdout.String(), "\n") != 1 || !strings.Contains(stdout.String(), `"playlist_id":"p2"`) {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should export the liked songs as a virtual playlist", func(t *testing.T) {
+		// Given a library with a saved track
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me/tracks?limit=50": `{"items": [{"added_at": "2024-01-02T03:04:05Z", "track": {"id": "t1", "name": "Song"}}]}`,
+		})
+
+		// When exporting the saved tracks
+		code := app.run([]string{"export", "--format", "ndjson", "--saved", "tracks"})
+
+		// Then only the liked songs should be exported, with their added date
+		if code != ExitOk || strings.Count(stdout.String(), "\n") != 1 ||
+			!strings.Contains(stdout.String(), `"playlist_id":"liked-songs"`) || !strings.Contains(stdout.String(), "2024-01-02T03:04:05Z") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should refuse unknown saved items", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+
+		if code := app.run([]string{"export", "--saved", "artists"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+
+	t.Run("it should leave out the excluded item types", func(t *testing.T) {
+		// Given a playlist with a track, an episode and a local file
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me/playlists?limit=50": `{"items": [{"id": "p1", "name": "Mixed"}]}`,
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [
+				{"track": {"type": "track", "id": "t1", "name": "Song"}},
+				{"track": {"type": "episode", "id": "e1", "name": "Episode"}},
+				{"is_local": true, "track": {"type": "track", "id": null, "uri": "spotify:local:a:b:c:1", "is_local": true}}
+			]}`,
+		})
+
+		// When exporting it without the episodes
+		code := app.run([]string{"export", "--format", "csv", "--columns", "type", "--exclude", "episode"})
+
+		// Then the track and the local file should be exported with their type
+		output := stdout.String()
+		if code != ExitOk || strings.Count(output, "\n") != 3 || strings.Contains(output, "e1") ||
+			!strings.Contains(output, ",track\n") || !strings.Contains(output, ",local\n") {
+			t.Errorf("Unexpected output (%d): %s", code, output)
+		}
+	})
+
+	t.Run("it should refuse unknown columns", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+
+		if code := app.run([]string{"export", "--format", "csv", "--columns", "bpm"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+func TestAvailabilityCommand(t *testing.T) {
+	t.Run("it should report the unavailable tracks in the market of the account", func(t *testing.T) {
+		// Given a playlist with a restricted track
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me/playlists?limit=50": `{"items": [{"id": "p1", "name": "Mix"}]}`,
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100&market=from_token": `{"items": [
+				{"track": {"id": "t1", "name": "Song", "is_playable": true}},
+				{"track": {"id": "t2", "name": "Gone", "artists": [{"name": "Artist"}], "is_playable": false, "restrictions": {"reason": "market"}}}
+			]}`,
+		})
+
+		// When checking its availability
+		code := app.run([]string{"availability", "p1"})
+
+		// Then the restricted track should be reported
+		if code != ExitOk || !strings.Contains(stdout.String(), "Mix #2 Artist - Gone: unavailable (market)\nMix: 2 checked, 1 unavailable, 0 relinked\n") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+}
+
+func TestStatsCommand(t *testing.T) {
+	routes := map[string]string{
+		"https://api.spotify.com/v1/me/playlists?limit=50": `{"items": [{"id": "p1", "name": "Mix"}]}`,
+		"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [
+			{"track": {"id": "t1", "uri": "spotify:track:t1", "artists": [{"id": "a1", "name": "Artist"}], "album": {"release_date": "1994"}, "duration_ms": 60000}},
+			{"track": {"id": "t2", "uri": "spotify:track:t2", "artists": [{"id": "a1", "name": "Artist"}], "explicit": true, "duration_ms": 60000}}
+		]}`,
+	}
+
+	t.Run("it should print the statistics of a playlist", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"stats", "p1"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "Explicit   1 (50%)\n") || !strings.Contains(stdout.String(), "Artist  2\n") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should include the liked songs without playlists", func(t *testing.T) {
+		libraryRoutes := map[string]string{
+			"https://api.spotify.com/v1/me/tracks?limit=50": `{"items": [{"track": {"id": "t3", "uri": "spotify:track:t3", "artists": [{"id": "a2", "name": "Other"}], "duration_ms": 60000}}]}`,
+		}
+		for url, body := range routes {
+			libraryRoutes[url] = body
+		}
+		app, stdout, _ := createApp(t, libraryRoutes)
+
+		code := app.run([]string{"stats", "--output", "json"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), `"playlists": 2`) || !strings.Contains(stdout.String(), `"tracks": 3`) {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should write an html report titled after the playlist", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"stats", "--output", "html", "p1"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "<title>Mix</title>") || !strings.Contains(stdout.String(), "<td>1990s</td>") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+}
+
+func TestSearchCommand(t *testing.T) {
+	routes := map[string]string{
+		"https://api.spotify.com/v1/search?limit=20&q=love+artist%3A%22Daft+Punk%22+year%3A2000-2010&type=track": `{"tracks": {"items": [
+			{"id": "t1", "uri": "spotify:track:t1", "name": "Digital Love", "artists": [{"name": "Daft Punk"}]}
+		]}}`,
+	}
+
+	t.Run("it should list the results of the query", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"search", "--artist", "Daft Punk", "--year", "2000-2010", "love"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "track  Digital Love  Daft Punk  spotify:track:t1\n") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should export the tracks found", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"search", "--artist", "Daft Punk", "--year", "2000-2010", "--format", "m3u8", "love"})
+
+		if code != ExitOk || !strings.HasSuffix(stdout.String(), "Daft Punk - Digital Love\nspotify:track:t1\n") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should preview the playlist of the tracks found", func(t *testing.T) {
+		app, stdout, _ := createApp(t, routes)
+
+		code := app.run([]string{"search", "--artist", "Daft Punk", "--year", "2000-2010", "--create", "Love", "--dry-run", "love"})
+
+		if code != ExitOk || !strings.Contains(stdout.String(), "Would add 1 tracks") {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should refuse an invalid year", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+
+		if code := app.run([]string{"search", "--year", "2010-2000", "love"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+func TestGenerateCommand(t *testing.T) {
+	t.Run("it should preview a playlist recommended from a genre and a playlist", func(t *testing.T) {
+		// Given a playlist and recommendations including one of its tracks
+		app, stdout, _ := createApp(t, map[string]string{
+			"https://api.spotify.com/v1/me/playlists?limit=50": `{"items": [{"id": "p1", "name": "Mix"}]}`,
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [
+				{"track": {"id": "t1", "uri": "spotify:track:t1", "name": "Old", "artists": [{"id": "a1", "name": "Artist"}]}}
+			]}`,
+			"https://api.spotify.com/v1/recommendations?limit=100&min_tempo=120&seed_artists=a1&seed_genres=house": `{"tracks": [
+				{"id": "t1", "uri": "spotify:track:t1", "name": "Old", "artists": [{"id": "a1", "name": "Artist"}]},
+				{"id": "t2", "uri": "spotify:track:t2", "name": "New", "artists": [{"id": "a2", "name": "Other"}]}
+			]}`,
+		})
+
+		// When generating a playlist in dry run
+		code := app.run([]string{"generate", "--name", "Fresh", "--seed-genres", "house", "--from", "p1", "--min", "tempo=120", "--size", "1", "--dry-run"})
+
+		// Then only the new track should be previewed
+		if code != ExitOk || stdout.String() != "Fresh (1 tracks)\n  1. Other - New\n" {
+			t.Errorf("Unexpected output (%d): %s", code, stdout.String())
+		}
+	})
+
+	t.Run("it should refuse unknown attributes", func(t *testing.T) {
+		app, _, _ := createApp(t, map[string]string{})
+
+		if code := app.run([]string{"generate", "--name", "Fresh", "--seed-genres", "house", "--min", "bpm=120"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+func TestConfigCommand(t *testing.T) {
+	t.Run("it should save the values and read them back", func(t *testing.T) {
+		// Given an app without configuration file
+		app, stdout, _ := createApp(t, nil)
+
+		// When setting the client id
+		if code := app.run([]string{"config", "set", "client_id", "abc"}); code != ExitOk {
+			t.Fatalf("Expected exit code %d, got %d", ExitOk, code)
+		}
+
+		// Then it should be read back
+		app.run([]string{"config", "get", "client_id"})
+		if stdout.String() != "abc\n" {
+			t.Errorf("Expected 'abc', got '%s'", stdout.String())
+		}
+	})
+
+	t.Run("it should read the environment overrides", func(t *testing.T) {
+		app, stdout, _ := createApp(t, nil)
+		t.Setenv("SPOTIFY_PLAYLIST_EXPORT_FORMAT", "csv")
+
+		app.run([]string{"config", "get", "export.format"})
+
+		if stdout.String() != "csv\n" {
+			t.Errorf("Expected 'csv', got '%s'", stdout.String())
+		}
+	})
+
+	t.Run("it should return a usage error for unknown keys", func(t *testing.T) {
+		app, _, _ := createApp(t, nil)
+
+		if code := app.run([]string{"config", "set", "color", "red"}); code != ExitUsage {
+			t.Errorf("Expected exit code %d, got %d", ExitUsage, code)
+		}
+	})
+}
+
+// Helpers
+
+// createApp returns an app using temporary directories, logged in and
+// answering the requests with the given routes unless they are nil
+func createApp(t *testing.T, routes map[string]string) (*App, *bytes.Buffer, *bytes.Buffer) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	app := newApp(stdout, stderr)
+	app.transport = &mockRoundTripper{func(req *http.Request) (*http.Response, error) {
+		body, ok := routes[req.URL.String()]
+		if !ok {
+			t.Errorf("Unexpected request to %s", req.URL.String())
+			return jsonResponse(http.StatusNotFound, ""), nil
+		}
+
+		return jsonResponse(http.StatusOK, body), nil
+	}}
+
+	if routes != nil {
+		saveStore(t, app, &auth.Store{Token: "token"})
+	}
+
+	return app, stdout, stderr
+}
+
+func saveStore(t *testing.T, app *App, store *auth.Store) {
+	if err := store.Save(app.credentialsPath()); err != nil {
+		t.Fatalf("Save returned an error: %s", err.Error())
+	}
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
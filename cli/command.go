@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command is a node of the command tree, it either runs an action or
+// dispatches to one of its subcommands
+type Command struct {
+	Name string
+	// Usage describes the arguments, e.g. "[flags] <playlist>"
+	Usage   string
+	Summary string
+	// Flags registers the flags of the command, bound to variables of the
+	// function building the command
+	Flags    func(flags *flag.FlagSet)
+	Run      func(app *App, args []string) error
+	Commands []*Command
+}
+
+// UsageError is returned for invalid arguments, the usage of the command is
+// printed along with the message
+type UsageError struct {
+	Command string
+	Message string
+}
+
+func (e *UsageError) Error() string {
+	return e.Message
+}
+
+func (c *Command) find(name string) *Command {
+	for _, command := range c.Commands {
+		if command.Name == name {
+			return command
+		}
+	}
+
+	return nil
+}
+
+// execute parses the global and command flags, then runs the command or its
+// subcommand named by the first argument
+func (c *Command) execute(app *App, path []string, args []string) error {
+	name := strings.Join(path[1:], " ")
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	app.Globals.register(flags)
+	if c.Flags != nil {
+		c.Flags(flags)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return c.printHelp(app.stdout, path)
+		}
+		return &UsageError{Command: name, Message: err.Error()}
+	}
+	args = flags.Args()
+
+	if len(c.Commands) > 0 && len(args) > 0 {
+		if command := c.find(args[0]); command != nil {
+			return command.execute(app, append(path, command.Name), args[1:])
+		}
+		if c.Run == nil {
+			return &UsageError{Command: name, Message: fmt.Sprintf("unknown command %q", args[0])}
+		}
+	}
+
+	if c.Run == nil {
+		return c.printHelp(app.stdout, path)
+	}
+	if err := app.Globals.validate(name); err != nil {
+		return err
+	}
+
+	return c.Run(app, args)
+}
+
+// printHelp writes the usage, the flags and the subcommands of the command,
+// the global flags are only listed by the root command
+func (c *Command) printHelp(writer io.Writer, path []string) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "Usage: %s", strings.Join(path, " "))
+	if c.Usage != "" {
+		fmt.Fprintf(&out, " %s", c.Usage)
+	}
+	fmt.Fprintf(&out, "\n\n%s\n", c.Summary)
+
+	if len(c.Commands) > 0 {
+		fmt.Fprintf(&out, "\nCommands:\n")
+		table := tabwriter.NewWriter(&out, 0, 4, 2, ' ', 0)
+		for _, command := range c.Commands {
+			fmt.Fprintf(table, "  %s\t%s\n", command.Name, command.Summary)
+		}
+		table.Flush()
+	}
+
+	flags := flag.NewFlagSet(path[0], flag.ContinueOnError)
+	if len(path) == 1 {
+		globals := defaultGlobals()
+		globals.register(flags)
+	}
+	if c.Flags != nil {
+		c.Flags(flags)
+	}
+
+	hasFlags := false
+	flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(&out, "\nFlags:\n")
+		flags.SetOutput(&out)
+		flags.PrintDefaults()
+	}
+
+	if len(path) > 1 {
+		fmt.Fprintf(&out, "\nRun '%s help' for the global flags.\n", path[0])
+	}
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+// helpCommand prints the help of the command named by its arguments
+func helpCommand(root *Command) *Command {
+	return &Command{
+		Name:    "help",
+		Usage:   "[command...]",
+		Summary: "Show the help of a command",
+		Run: func(app *App, args []string) error {
+			command, path := root, []string{root.Name}
+			for _, name := range args {
+				if command = command.find(name); command == nil {
+					return &UsageError{Command: "help", Message: fmt.Sprintf("unknown command %q", strings.Join(append(path[1:], name), " "))}
+				}
+				path = append(path, name)
+			}
+
+			return command.printHelp(app.stdout, path)
+		},
+	}
+}
+
+// usageErrorf returns a usage error for the command
+func usageErrorf(command string, format string, args ...any) error {
+	return &UsageError{Command: command, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/config"
+)
+
+func configCommand() *Command {
+	return &Command{
+		Name:    "config",
+		Usage:   "<command>",
+		Summary: "Read and write the configuration file",
+		Commands: []*Command{
+			{
+				Name:    "get",
+				Usage:   "<key>",
+				Summary: "Print the value of a key, including the environment overrides",
+				Run: func(app *App, args []string) error {
+					if len(args) != 1 {
+						return usageErrorf("config get", "expected a key")
+					}
+
+					settings, err := app.Configuration()
+					if err != nil {
+						return err
+					}
+
+					value, err := settings.Get(args[0])
+					if err != nil {
+						return usageErrorf("config get", "%s", err.Error())
+					}
+
+					_, err = fmt.Fprintln(app.stdout, value)
+					return err
+				},
+			},
+			{
+				Name:    "set",
+				Usage:   "<key> <value>",
+				Summary: "Write the value of a key to the configuration file",
+				Run: func(app *App, args []string) error {
+					if len(args) != 2 {
+						return usageErrorf("config set", "expected a key and a value")
+					}
+
+					// The environment overrides must not be saved
+					settings, err := config.LoadFile(app.Config)
+					if err != nil {
+						return err
+					}
+
+					if err := settings.Set(args[0], args[1]); err != nil {
+						return usageErrorf("config set", "%s", err.Error())
+					}
+
+					return settings.Save(app.Config)
+				},
+			},
+			{
+				Name:    "show",
+				Summary: "Print every key with its value and the environment variable overriding it",
+				Run: func(app *App, args []string) error {
+					output, err := app.output("config show", OutputText, OutputTable, OutputJson)
+					if err != nil {
+						return err
+					}
+
+					settings, err := app.Configuration()
+					if err != nil {
+						return err
+					}
+
+					values := map[string]string{}
+					rows := make([][]string, 0, len(config.Keys()))
+					for _, name := range config.Keys() {
+						values[name], _ = settings.Get(name)
+						rows = append(rows, []string{name, values[name], config.EnvName(name)})
+					}
+
+					if output == OutputJson {
+						return writeJson(app.stdout, values)
+					}
+
+					fmt.Fprintf(app.stdout, "File: %s\n\n", app.Config)
+					return writeTable(app.stdout, []string{"key", "value", "environment"}, rows)
+				},
+			},
+		},
+	}
+}
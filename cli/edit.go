@@ -0,0 +1,455 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/dedupe"
+	"prisco.dev/spotify-playlist/diff"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+	"prisco.dev/spotify-playlist/importer"
+	"prisco.dev/spotify-playlist/ops"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+func importCommand() *Command {
+	var options restore.Options
+	var minConfidence float64
+
+	return &Command{
+		Name:    "import",
+		Usage:   "[flags] <file>",
+		Summary: "Create a playlist from a M3U, XSPF or CSV file, matching its tracks on Spotify",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&options.Name, "name", "", "`name` of the playlist, the one of the file by default")
+			flags.Float64Var(&minConfidence, "min-confidence", importer.DefaultMinConfidence, "minimum `confidence` of a match, between 0 and 1")
+			flags.BoolVar(&options.DryRun, "dry-run", false, "report the matches without creating the playlist")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("import", "expected the file to import")
+			}
+
+			name, entries, err := importer.ParseFile(args[0])
+			if err != nil {
+				return err
+			}
+			if options.Name != "" {
+				name = options.Name
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			report, err := importer.Resolve(client, entries, minConfidence)
+			if err != nil {
+				return err
+			}
+			if err := importer.PrintReport(app.stdout, report); err != nil {
+				return err
+			}
+			if len(report.Matches) == 0 {
+				return fmt.Errorf("no track of %s was found", args[0])
+			}
+
+			options.Progress = app.progress()
+			result, err := importer.CreatePlaylist(client, name, report, options)
+			if err != nil {
+				return err
+			}
+
+			return printRestored(app, result, options.DryRun)
+		},
+	}
+}
+
+func dedupeCommand() *Command {
+	var options dedupe.Options
+	var remove bool
+
+	return &Command{
+		Name:    "dedupe",
+		Usage:   "[flags] <playlist...>",
+		Summary: "Find the duplicate tracks within and across playlists",
+		Flags: func(flags *flag.FlagSet) {
+			flags.BoolVar(&options.Fuzzy, "fuzzy", false, "also report different releases of the same song")
+			flags.Float64Var(&options.MinScore, "min-score", dedupe.DefaultMinScore, "minimum `score` of a fuzzy duplicate, between 0 and 1")
+			flags.BoolVar(&remove, "remove", false, "remove the duplicates from their playlist")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) == 0 {
+				return usageErrorf("dedupe", "expected at least a playlist")
+			}
+
+			entries, err := liveEntries(app, args)
+			if err != nil {
+				return err
+			}
+
+			duplicates := dedupe.Find(entries, options)
+			if err := dedupe.PrintReport(app.stdout, duplicates); err != nil {
+				return err
+			}
+			if !remove || len(duplicates) == 0 {
+				return nil
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+			if err := dedupe.Remove(client, duplicates); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(app.stdout, "Removed %d duplicates\n", len(duplicates))
+			return err
+		},
+	}
+}
+
+func sortCommand() *Command {
+	var options ops.SortOptions
+	var key string
+
+	return &Command{
+		Name:    "sort",
+		Usage:   "[flags] <playlist>",
+		Summary: "Permanently reorder a playlist",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&key, "by", "", "key `expression`, e.g. added_at, artist, album, release_date, tempo, duration or popularity")
+			flags.BoolVar(&options.Descending, "desc", false, "sort in descending order")
+			flags.BoolVar(&options.DryRun, "dry-run", false, "compute the moves without reordering the playlist")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("sort", "expected the playlist to sort")
+			}
+			if key == "" {
+				return usageErrorf("sort", "a key is required")
+			}
+
+			expression, err := expr.Parse(key)
+			if err != nil {
+				return usageErrorf("sort", "invalid key: %s", err.Error())
+			}
+
+			entries, err := liveEntries(app, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			options.Features = client
+			options.Progress = app.progress()
+			moves, err := ops.Sort(client, entries[0], expression, options)
+			if err != nil {
+				return err
+			}
+
+			if options.DryRun {
+				return nil
+			}
+			_, err = fmt.Fprintf(app.stdout, "Sorted %s with %d moves\n", entries[0].Playlist.Name, len(moves))
+			return err
+		},
+	}
+}
+
+// planFlags are the flags shared by merge, split and filter
+type planFlags struct {
+	dryRun bool
+}
+
+func (p *planFlags) register(flags *flag.FlagSet) {
+	flags.BoolVar(&p.dryRun, "dry-run", false, "preview the playlists without creating them")
+}
+
+// create previews the planned playlists, then creates them unless in dry run
+func (p *planFlags) create(app *App, plans []ops.Plan) error {
+	if err := ops.PrintPlans(app.stdout, plans); err != nil {
+		return err
+	}
+	if p.dryRun {
+		return nil
+	}
+
+	client, err := app.Client()
+	if err != nil {
+		return err
+	}
+
+	results, err := ops.Create(client, plans, restore.Options{Progress: app.progress()})
+	for _, result := range results {
+		printRestored(app, result, false)
+	}
+
+	return err
+}
+
+func mergeCommand() *Command {
+	var flags planFlags
+	var options ops.MergeOptions
+	var name string
+
+	return &Command{
+		Name:    "merge",
+		Usage:   "[flags] <playlist...>",
+		Summary: "Create a playlist with the tracks of several playlists",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&name, "name", "", "`name` of the merged playlist")
+			set.BoolVar(&options.Dedupe, "dedupe", false, "keep only the first occurrence of every track")
+			set.BoolVar(&options.Fuzzy, "fuzzy", false, "also drop different releases of the same song when deduping")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) < 2 {
+				return usageErrorf("merge", "expected at least two playlists")
+			}
+			if name == "" {
+				return usageErrorf("merge", "a name is required")
+			}
+
+			entries, err := liveEntries(app, args)
+			if err != nil {
+				return err
+			}
+
+			return flags.create(app, []ops.Plan{ops.Merge(entries, name, options)})
+		},
+	}
+}
+
+func splitCommand() *Command {
+	var flags planFlags
+	var options ops.SplitOptions
+
+	return &Command{
+		Name:    "split",
+		Usage:   "[flags] <playlist>",
+		Summary: "Create a playlist per decade, artist, genre or fixed size group of tracks",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&options.By, "by", ops.SplitByDecade, "`criteria`: decade, artist, genre or size")
+			set.IntVar(&options.Size, "size", 100, "number of `tracks` per playlist when splitting by size")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("split", "expected the playlist to split")
+			}
+
+			entries, err := liveEntries(app, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			options.Artists = client
+			plans, err := ops.Split(entries[0], options)
+			if err != nil {
+				return err
+			}
+
+			return flags.create(app, plans)
+		},
+	}
+}
+
+func filterCommand() *Command {
+	var flags planFlags
+	var condition, name string
+
+	return &Command{
+		Name:    "filter",
+		Usage:   "[flags] <playlist>",
+		Summary: "Create a playlist with the tracks matching an expression",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&condition, "where", "", "`condition` on the tracks, e.g. 'year >= 2000 && !explicit'")
+			set.StringVar(&name, "name", "", "`name` of the filtered playlist")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("filter", "expected the playlist to filter")
+			}
+			if condition == "" || name == "" {
+				return usageErrorf("filter", "a condition and a name are required")
+			}
+
+			expression, err := expr.Parse(condition)
+			if err != nil {
+				return usageErrorf("filter", "invalid condition: %s", err.Error())
+			}
+
+			entries, err := liveEntries(app, args)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			plan, err := ops.Filter(entries[0], expression, name, client)
+			if err != nil {
+				return err
+			}
+
+			return flags.create(app, []ops.Plan{plan})
+		},
+	}
+}
+
+func generateCommand() *Command {
+	var flags planFlags
+	var options ops.GenerateOptions
+	var name, artists, tracks, genres, from string
+	bounds := map[string]*string{"min": new(string), "max": new(string), "target": new(string)}
+
+	return &Command{
+		Name:    "generate",
+		Usage:   "[flags]",
+		Summary: "Create a playlist of tracks recommended from seed artists, tracks, genres or playlist",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&name, "name", "", "`name` of the generated playlist")
+			set.IntVar(&options.Size, "size", 30, "number of `tracks` of the playlist")
+			set.StringVar(&artists, "seed-artists", "", "comma separated artist `ids` to start from")
+			set.StringVar(&tracks, "seed-tracks", "", "comma separated track `ids` to start from")
+			set.StringVar(&genres, "seed-genres", "", "comma separated `genres` to start from")
+			set.StringVar(&from, "from", "", "`playlist` whose top artists complete the seeds, its tracks are left out")
+			set.BoolVar(&options.Fuzzy, "fuzzy", false, "also leave out the different releases of the songs of the playlist")
+			set.StringVar(&options.Seeds.Market, "market", "", "only the tracks playable in the `market`, from_token for the one of the account")
+			set.StringVar(bounds["min"], "min", "", "comma separated minimum `values` of attributes, e.g. tempo=120,energy=0.6")
+			set.StringVar(bounds["max"], "max", "", "comma separated maximum `values` of attributes")
+			set.StringVar(bounds["target"], "target", "", "comma separated target `values` of attributes")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 0 {
+				return usageErrorf("generate", "unexpected arguments %v", args)
+			}
+			if name == "" {
+				return usageErrorf("generate", "a name is required")
+			}
+			if options.Size < 1 {
+				return usageErrorf("generate", "invalid size %d, expected a positive number", options.Size)
+			}
+
+			options.Seeds.SeedArtists = splitList(artists)
+			options.Seeds.SeedTracks = splitList(tracks)
+			options.Seeds.SeedGenres = splitList(genres)
+			seeds := len(options.Seeds.SeedArtists) + len(options.Seeds.SeedTracks) + len(options.Seeds.SeedGenres)
+			if seeds > api.MaxSeeds || (seeds == 0 && from == "") {
+				return usageErrorf("generate", "expected between 1 and %d seeds, or a playlist", api.MaxSeeds)
+			}
+
+			options.Seeds.Attributes = map[string]api.AttributeRange{}
+			for _, bound := range []string{"min", "max", "target"} {
+				if err := parseAttributes(options.Seeds.Attributes, bound, *bounds[bound]); err != nil {
+					return usageErrorf("generate", "invalid --%s: %s", bound, err.Error())
+				}
+			}
+
+			if from != "" {
+				entries, err := liveEntries(app, []string{from})
+				if err != nil {
+					return err
+				}
+				options.Source = &entries[0]
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			plan, err := ops.Generate(client, name, options)
+			if err != nil {
+				return err
+			}
+			if len(plan.Tracks) == 0 {
+				return fmt.Errorf("no track was recommended")
+			}
+
+			return flags.create(app, []ops.Plan{plan})
+		},
+	}
+}
+
+// parseAttributes sets a bound, min, max or target, of the attributes
+// listed as name=value pairs
+func parseAttributes(attributes map[string]api.AttributeRange, bound string, value string) error {
+	for _, pair := range splitList(value) {
+		name, text, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected name=value, got %s", pair)
+		}
+		if !slices.Contains(api.TunableAttributes, name) {
+			return fmt.Errorf("unknown attribute %s, expected one of %s", name, strings.Join(api.TunableAttributes, ", "))
+		}
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value of %s: %s", name, text)
+		}
+
+		attribute := attributes[name]
+		switch bound {
+		case "min":
+			attribute.Min = &number
+		case "max":
+			attribute.Max = &number
+		case "target":
+			attribute.Target = &number
+		}
+		attributes[name] = attribute
+	}
+
+	return nil
+}
+
+// splitList splits a comma separated list, ignoring the empty values
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
+// liveEntries fetches the current tracks of the playlists
+func liveEntries(app *App, ids []string) ([]export.PlaylistEntry, error) {
+	source, err := app.Source()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]export.PlaylistEntry, 0, len(ids))
+	for _, id := range ids {
+		entry, err := diff.LoadLive(source, id)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"prisco.dev/spotify-playlist/diff"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+// live designates the current version of a playlist in place of a path
+const live = "live"
+
+func diffCommand() *Command {
+	var playlistId string
+
+	return &Command{
+		Name:    "diff",
+		Usage:   "[flags] <old> <new|live>",
+		Summary: "Compare two exports or snapshots of a playlist, or one of them with the live playlist",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&playlistId, "playlist", "", "`id` of the playlist, required for exports holding several playlists")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 2 {
+				return usageErrorf("diff", "expected the old and the new version of the playlist")
+			}
+			output, err := app.output("diff", OutputText, OutputJson, OutputMarkdown)
+			if err != nil {
+				return err
+			}
+
+			old, err := diff.Load(args[0], playlistId)
+			if err != nil {
+				return err
+			}
+
+			var new export.PlaylistEntry
+			if args[1] == live {
+				source, err := app.Source()
+				if err != nil {
+					return err
+				}
+				new, err = diff.LoadLive(source, old.Playlist.Id)
+				if err != nil {
+					return err
+				}
+			} else if new, err = diff.Load(args[1], old.Playlist.Id); err != nil {
+				return err
+			}
+
+			return diff.Render(app.stdout, diff.Compare(old, new), output)
+		},
+	}
+}
+
+func restoreCommand() *Command {
+	var playlistId string
+	var options restore.Options
+
+	return &Command{
+		Name:    "restore",
+		Usage:   "[flags] <export|archive>",
+		Summary: "Recreate a playlist from an export or a backup archive",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&playlistId, "playlist", "", "`id` of the playlist, required for exports holding several playlists")
+			flags.StringVar(&options.Name, "name", "", "`name` of the restored playlist, the saved one by default")
+			flags.BoolVar(&options.DryRun, "dry-run", false, "report what would be restored without creating the playlist")
+		},
+		Run: func(app *App, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("restore", "expected the export or archive to restore")
+			}
+			output, err := app.output("restore", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			entry, err := diff.Load(args[0], playlistId)
+			if err != nil {
+				return err
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+
+			options.Progress = app.progress()
+			result, err := restore.Restore(client, entry, options)
+			if err != nil {
+				return err
+			}
+
+			if output == OutputJson {
+				return writeJson(app.stdout, result)
+			}
+			return printRestored(app, result, options.DryRun)
+		},
+	}
+}
+
+func printRestored(app *App, result *restore.Result, dryRun bool) error {
+	if dryRun {
+		fmt.Fprintf(app.stdout, "Would add %d tracks", result.Added)
+	} else {
+		fmt.Fprintf(app.stdout, "Added %d tracks to playlist %s", result.Added, result.PlaylistId)
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(app.stdout, ", %s", app.paint(app.stdout, yellow, fmt.Sprintf("%d skipped", len(result.Skipped))))
+	}
+
+	_, err := fmt.Fprintln(app.stdout)
+	return err
+}
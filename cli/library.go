@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"prisco.dev/spotify-playlist/backup"
+	"prisco.dev/spotify-playlist/enrich"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/library"
+	"prisco.dev/spotify-playlist/snapshot"
+)
+
+const (
+	enrichFeatures = "features"
+	enrichArtists  = "artists"
+	enrichAlbums   = "albums"
+)
+
+// exportFlags are the flags shared by export and sync
+type exportFlags struct {
+	format  string
+	columns string
+	enrich  string
+	exclude string
+}
+
+func (e *exportFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&e.format, "format", "", "export `format`, 'list' prints the available formats, export.format of the config by default")
+	flags.StringVar(&e.columns, "columns", "", "comma separated optional `columns` of the csv format")
+	e.registerTracks(flags)
+}
+
+// registerTracks registers the flags selecting and enriching the tracks only
+func (e *exportFlags) registerTracks(flags *flag.FlagSet) {
+	flags.StringVar(&e.enrich, "enrich", "", "comma separated `details` to look up: features, artists, albums")
+	flags.StringVar(&e.exclude, "exclude", "", "comma separated item `types` to leave out: episode, local")
+}
+
+// parseFormat returns the requested format, or the one of the config
+func (e *exportFlags) parseFormat(app *App, command string) (export.Format, error) {
+	name := e.format
+	if name == "" {
+		settings, err := app.Configuration()
+		if err != nil {
+			return export.Format{}, err
+		}
+		name = settings.Export.Format
+	}
+
+	format, err := export.ParseFormat(name)
+	if err != nil {
+		return export.Format{}, usageErrorf(command, "%s", err.Error())
+	}
+
+	return format, nil
+}
+
+// options returns the export options, leaving out the excluded item types
+// and enriching the tracks with the details requested explicitly or needed
+// by the columns. The returned function saves the lookup caches.
+func (e *exportFlags) options(app *App, command string) (export.Options, func() error, error) {
+	columns, err := export.ParseColumns(e.columns)
+	if err != nil {
+		return export.Options{}, nil, usageErrorf(command, "%s", err.Error())
+	}
+
+	requested := map[string]bool{}
+	for _, name := range strings.Split(e.enrich, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case enrichFeatures, enrichArtists, enrichAlbums:
+			requested[name] = true
+		default:
+			return export.Options{}, nil, usageErrorf(command, "unknown details %q", name)
+		}
+	}
+	for _, column := range columns {
+		requested[enrichFeatures] = requested[enrichFeatures] || slices.Contains(export.AudioFeatureColumns, column)
+		requested[enrichArtists] = requested[enrichArtists] || slices.Contains(export.ArtistColumns, column)
+		requested[enrichAlbums] = requested[enrichAlbums] || slices.Contains(export.AlbumColumns, column)
+	}
+
+	options := export.Options{Columns: columns}
+	var excluded []string
+	for _, kind := range strings.Split(e.exclude, ",") {
+		switch kind = strings.TrimSpace(kind); kind {
+		case "":
+		case export.TypeEpisode, export.TypeLocal:
+			excluded = append(excluded, kind)
+		default:
+			return export.Options{}, nil, usageErrorf(command, "unknown item type %q", kind)
+		}
+	}
+	if len(excluded) > 0 {
+		// Excluded items are dropped before being enriched
+		options.Stages = append(options.Stages, export.Exclude(excluded...))
+	}
+
+	save := func() error { return nil }
+	if len(requested) == 0 {
+		return options, save, nil
+	}
+
+	client, err := app.Client()
+	if err != nil {
+		return export.Options{}, nil, err
+	}
+
+	if requested[enrichFeatures] {
+		// Audio features never change, they are kept across runs
+		cache, err := enrich.LoadCache[*export.AudioFeatures](filepath.Join(app.cacheDirectory(), "audio_features.json"))
+		if err != nil {
+			return export.Options{}, nil, err
+		}
+		options.Stages = append(options.Stages, enrich.FeaturesStage(client, cache))
+		save = cache.Save
+	}
+	if requested[enrichArtists] {
+		options.Stages = append(options.Stages, enrich.ArtistsStage(client, nil))
+	}
+	if requested[enrichAlbums] {
+		options.Stages = append(options.Stages, enrich.AlbumsStage(client, nil))
+	}
+
+	return options, save, nil
+}
+
+func exportCommand() *Command {
+	var flags exportFlags
+	var directory, file, saved string
+
+	return &Command{
+		Name:    "export",
+		Usage:   "[flags] [playlist...]",
+		Summary: "Export playlists, all of them when none is given",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&directory, "dir", "", "write a file per playlist into the `directory`")
+			set.StringVar(&file, "file", "", "write the export to the `file` instead of the standard output")
+			set.StringVar(&saved, "saved", "", "comma separated saved `items` to export as virtual playlists: tracks, albums, shows, episodes")
+		},
+		Run: func(app *App, args []string) error {
+			if flags.format == "list" {
+				return export.PrintFormats(app.stdout)
+			}
+
+			format, err := flags.parseFormat(app, "export")
+			if err != nil {
+				return err
+			}
+			if directory != "" && file != "" {
+				return usageErrorf("export", "--dir and --file cannot be used together")
+			}
+
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+			options, save, err := flags.options(app, "export")
+			if err != nil {
+				return err
+			}
+			selected, err := selectSaved(selectPlaylists(source, args), saved, len(args) > 0)
+			if err != nil {
+				return usageErrorf("export", "%s", err.Error())
+			}
+
+			if directory != "" {
+				err = export.ExportToDirectory(selected, format, directory, options)
+			} else {
+				err = exportToFile(selected, format, file, options, app)
+			}
+			if err != nil {
+				return err
+			}
+
+			return save()
+		},
+	}
+}
+
+func exportToFile(source export.PlaylistSource, format export.Format, path string, options export.Options, app *App) error {
+	if path == "" {
+		return export.Export(source, format, app.stdout, options)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := export.Export(source, format, file, options); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func syncCommand() *Command {
+	var flags exportFlags
+	var directory string
+
+	return &Command{
+		Name:    "sync",
+		Usage:   "[flags]",
+		Summary: "Export the playlists changed since the last sync",
+		Flags: func(set *flag.FlagSet) {
+			flags.register(set)
+			set.StringVar(&directory, "dir", "", "`directory` holding a file per playlist, export.dir of the config by default")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("sync", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			format, err := flags.parseFormat(app, "sync")
+			if err != nil {
+				return err
+			}
+			if directory, err = exportDirectory(app, directory); err != nil {
+				return err
+			}
+
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+			options, save, err := flags.options(app, "sync")
+			if err != nil {
+				return err
+			}
+
+			report, err := snapshot.Sync(source, format, directory, options)
+			if err != nil {
+				return err
+			}
+			if err := save(); err != nil {
+				return err
+			}
+
+			if output == OutputJson {
+				return writeJson(app.stdout, report)
+			}
+			_, err = fmt.Fprintln(app.stdout, report)
+			return err
+		},
+	}
+}
+
+func backupCommand() *Command {
+	var directory string
+
+	return &Command{
+		Name:    "backup",
+		Usage:   "[flags]",
+		Summary: "Snapshot the whole library into a new archive",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&directory, "dir", "", "`directory` receiving the archive, export.dir of the config by default")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("backup", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			if directory, err = exportDirectory(app, directory); err != nil {
+				return err
+			}
+
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+
+			archive, manifest, err := backup.Create(source, directory, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if output == OutputJson {
+				return writeJson(app.stdout, manifest)
+			}
+			_, err = fmt.Fprintf(app.stdout, "%s (%d files)\n", archive, len(manifest.Files))
+			return err
+		},
+	}
+}
+
+// exportDirectory returns the directory, or the one of the config
+func exportDirectory(app *App, directory string) (string, error) {
+	if directory != "" {
+		return directory, nil
+	}
+
+	settings, err := app.Configuration()
+	if err != nil {
+		return "", err
+	}
+
+	return settings.Export.Directory, nil
+}
+
+// selection restricts a source to the given playlists, in their order
+type selection struct {
+	export.PlaylistSource
+	ids []string
+}
+
+func selectPlaylists(source export.PlaylistSource, ids []string) export.PlaylistSource {
+	if len(ids) == 0 {
+		return source
+	}
+
+	return selection{source, ids}
+}
+
+func (s selection) Playlists() ([]export.Playlist, error) {
+	playlists, err := s.PlaylistSource.Playlists()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]export.Playlist, 0, len(s.ids))
+	for _, id := range s.ids {
+		index := slices.IndexFunc(playlists, func(playlist export.Playlist) bool { return playlist.Id == id })
+		if index < 0 {
+			return nil, fmt.Errorf("playlist %s not found in the library", id)
+		}
+		selected = append(selected, playlists[index])
+	}
+
+	return selected, nil
+}
+
+// savedSelection adds the virtual playlists of the saved items to a source,
+// the playlists of the source being dropped unless some were selected
+type savedSelection struct {
+	export.PlaylistSource
+	saved     []export.Playlist
+	playlists bool
+}
+
+func selectSaved(source export.PlaylistSource, names string, playlists bool) (export.PlaylistSource, error) {
+	if names == "" {
+		return source, nil
+	}
+
+	saved, err := library.Collections(strings.Split(names, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return savedSelection{source, saved, playlists}, nil
+}
+
+func (s savedSelection) Playlists() ([]export.Playlist, error) {
+	if !s.playlists {
+		return s.saved, nil
+	}
+
+	playlists, err := s.PlaylistSource.Playlists()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(playlists, s.saved...), nil
+}
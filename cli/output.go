@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// ANSI colors of the messages
+const (
+	red    = "31"
+	green  = "32"
+	yellow = "33"
+)
+
+// paint colors the text written to writer when colors are enabled, auto
+// enables them when the writer is a terminal and NO_COLOR is not set
+func (a *App) paint(writer io.Writer, color string, text string) string {
+	enabled := false
+	switch a.Color {
+	case ColorAlways:
+		enabled = true
+	case ColorAuto:
+		enabled = os.Getenv("NO_COLOR") == "" && isTerminal(writer)
+	}
+
+	if !enabled {
+		return text
+	}
+
+	return "\x1b[" + color + "m" + text + "\x1b[0m"
+}
+
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writeJson writes the value as indented json
+func writeJson(writer io.Writer, value any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+// writeTable writes aligned columns under an upper case header
+func writeTable(writer io.Writer, header []string, rows [][]string) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(table, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(row, "\t"))
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/csv"
+	"flag"
+	"regexp"
+	"strconv"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+// playlistFilter keeps the playlists matching every criteria set
+type playlistFilter struct {
+	// owner keeps the playlists of this user when not empty
+	owner     string
+	name      *regexp.Regexp
+	minTracks int
+}
+
+func (f playlistFilter) apply(playlists []export.Playlist) []export.Playlist {
+	kept := []export.Playlist{}
+	for _, playlist := range playlists {
+		switch {
+		case f.owner != "" && playlist.Owner != f.owner:
+		case f.name != nil && !f.name.MatchString(playlist.Name):
+		case playlist.TrackCount < f.minTracks:
+		default:
+			kept = append(kept, playlist)
+		}
+	}
+
+	return kept
+}
+
+var playlistHeader = []string{"id", "name", "owner", "tracks", "public", "collaborative", "snapshot_id"}
+
+func playlistsCommand() *Command {
+	var owned bool
+	var nameRegex string
+	var minTracks int
+
+	return &Command{
+		Name:    "playlists",
+		Usage:   "[flags]",
+		Summary: "List the owned, followed and collaborative playlists",
+		Flags: func(flags *flag.FlagSet) {
+			flags.BoolVar(&owned, "owned", false, "only list the playlists owned by the user")
+			flags.StringVar(&nameRegex, "name-regex", "", "only list the playlists whose name matches the `regex`")
+			flags.IntVar(&minTracks, "min-tracks", 0, "only list the playlists with at least `n` tracks")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("playlists", OutputText, OutputTable, OutputCsv, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			filter := playlistFilter{minTracks: minTracks}
+			if nameRegex != "" {
+				if filter.name, err = regexp.Compile(nameRegex); err != nil {
+					return usageErrorf("playlists", "invalid name regex: %s", err.Error())
+				}
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+			if owned {
+				user, err := client.CurrentUser()
+				if err != nil {
+					return err
+				}
+				filter.owner = user.Id
+			}
+
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+			playlists, err := source.Playlists()
+			if err != nil {
+				return err
+			}
+			playlists = filter.apply(playlists)
+
+			rows := make([][]string, 0, len(playlists))
+			for _, playlist := range playlists {
+				rows = append(rows, []string{
+					playlist.Id,
+					playlist.Name,
+					playlist.Owner,
+					strconv.Itoa(playlist.TrackCount),
+					strconv.FormatBool(playlist.Public),
+					strconv.FormatBool(playlist.Collaborative),
+					playlist.SnapshotId,
+				})
+			}
+
+			switch output {
+			case OutputJson:
+				return writeJson(app.stdout, playlists)
+			case OutputCsv:
+				writer := csv.NewWriter(app.stdout)
+				writer.Write(playlistHeader)
+				writer.WriteAll(rows)
+				return writer.Error()
+			default:
+				return writeTable(app.stdout, playlistHeader, rows)
+			}
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+func rootCommand() *Command {
+	root := &Command{
+		Name:    Name,
+		Usage:   "[flags] <command> [flags] [arguments]",
+		Summary: "Export, back up and edit your Spotify playlists.",
+		Commands: []*Command{
+			loginCommand(),
+			logoutCommand(),
+			whoamiCommand(),
+			playlistsCommand(),
+			exportCommand(),
+			syncCommand(),
+			backupCommand(),
+			diffCommand(),
+			restoreCommand(),
+			importCommand(),
+			dedupeCommand(),
+			availabilityCommand(),
+			statsCommand(),
+			searchCommand(),
+			sortCommand(),
+			mergeCommand(),
+			splitCommand(),
+			filterCommand(),
+			generateCommand(),
+			cacheCommand(),
+			configCommand(),
+		},
+	}
+	root.Commands = append(root.Commands, helpCommand(root))
+
+	return root
+}
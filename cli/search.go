@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/restore"
+	"prisco.dev/spotify-playlist/search"
+)
+
+func searchCommand() *Command {
+	var query api.Query
+	var options search.Options
+	var year, types, format string
+	var create restore.Options
+
+	return &Command{
+		Name:    "search",
+		Usage:   "[flags] [keywords...]",
+		Summary: "Search the catalog, exporting the tracks found or creating a playlist with them",
+		Flags: func(flags *flag.FlagSet) {
+			flags.StringVar(&query.Track, "track", "", "filter the tracks by `name`")
+			flags.StringVar(&query.Artist, "artist", "", "filter by artist `name`")
+			flags.StringVar(&query.Album, "album", "", "filter by album `name`")
+			flags.StringVar(&year, "year", "", "filter by release `year` or range of years, e.g. 1999 or 1990-1999")
+			flags.StringVar(&query.Isrc, "isrc", "", "filter the tracks by `isrc`")
+			flags.StringVar(&query.Genre, "genre", "", "filter the artists and tracks by `genre`")
+			flags.BoolVar(&query.New, "new", false, "only the albums released in the past two weeks")
+			flags.StringVar(&types, "type", api.SearchTrack, "comma separated result `types`: track, album, artist, playlist, show, episode")
+			flags.StringVar(&options.Market, "market", "", "only the content playable in the `market`, from_token for the one of the account")
+			flags.IntVar(&options.Limit, "limit", search.DefaultLimit, "number of `results` per type")
+			flags.StringVar(&format, "format", "", "export the tracks and episodes found in the `format` instead of listing the results")
+			flags.StringVar(&create.Name, "create", "", "create a playlist of the `name` with the tracks and episodes found")
+			flags.BoolVar(&create.DryRun, "dry-run", false, "report the playlist which would be created without creating it")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("search", OutputText, OutputJson)
+			if err != nil {
+				return err
+			}
+
+			query.Keywords = strings.Join(args, " ")
+			if query.FromYear, query.ToYear, err = parseYears(year); err != nil {
+				return usageErrorf("search", "invalid year %q, expected a year or a range such as 1990-1999", year)
+			}
+			if query.String() == "" {
+				return usageErrorf("search", "expected keywords or a filter")
+			}
+			for _, kind := range strings.Split(types, ",") {
+				switch kind = strings.TrimSpace(kind); kind {
+				case api.SearchTrack, api.SearchAlbum, api.SearchArtist, api.SearchPlaylist, api.SearchShow, api.SearchEpisode:
+					options.Types = append(options.Types, kind)
+				default:
+					return usageErrorf("search", "unknown type %q", kind)
+				}
+			}
+			if options.Limit < 1 || options.Limit > api.MaxSearchOffset {
+				return usageErrorf("search", "invalid limit %d, expected a number between 1 and %d", options.Limit, api.MaxSearchOffset)
+			}
+			if format != "" && create.Name != "" {
+				return usageErrorf("search", "--format and --create cannot be used together")
+			}
+
+			var exportFormat export.Format
+			if format != "" {
+				if exportFormat, err = export.ParseFormat(format); err != nil {
+					return usageErrorf("search", "%s", err.Error())
+				}
+			}
+
+			client, err := app.Client()
+			if err != nil {
+				return err
+			}
+			results, err := search.Search(client, query, options)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case format != "":
+				return export.Export(results.Source("Search: "+results.Query), exportFormat, app.stdout, export.Options{})
+			case create.Name != "":
+				if len(results.Tracks) == 0 {
+					return fmt.Errorf("no track found for %q", results.Query)
+				}
+				create.Progress = app.progress()
+				result, err := restore.Restore(client, results.Entry(create.Name), create)
+				if err != nil {
+					return err
+				}
+				return printRestored(app, result, create.DryRun)
+			case output == OutputJson:
+				return writeJson(app.stdout, results)
+			default:
+				return search.PrintResults(app.stdout, results)
+			}
+		},
+	}
+}
+
+// parseYears parses a year or a range of years such as 1990-1999
+func parseYears(value string) (int, int, error) {
+	if value == "" {
+		return 0, 0, nil
+	}
+
+	from, to, isRange := strings.Cut(value, "-")
+	fromYear, err := strconv.Atoi(from)
+	if err != nil || !isRange {
+		return fromYear, 0, err
+	}
+
+	toYear, err := strconv.Atoi(to)
+	if err == nil && toYear < fromYear {
+		err = fmt.Errorf("the range ends before it starts")
+	}
+	return fromYear, toYear, err
+}
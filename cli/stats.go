@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"flag"
+	"io"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/stats"
+)
+
+func statsCommand() *Command {
+	var flags exportFlags
+	var top int
+	var saved string
+
+	return &Command{
+		Name:    "stats",
+		Usage:   "[flags] [playlist...]",
+		Summary: "Compute the statistics of playlists, the playlists and Liked Songs when none is given",
+		Flags: func(set *flag.FlagSet) {
+			flags.registerTracks(set)
+			set.IntVar(&top, "top", stats.DefaultTop, "number of `entries` of the rankings")
+			set.StringVar(&saved, "saved", "", "comma separated saved `items` to include as virtual playlists: tracks, albums, shows, episodes, tracks by default without playlists")
+		},
+		Run: func(app *App, args []string) error {
+			output, err := app.output("stats", OutputText, OutputTable, OutputJson, OutputHtml)
+			if err != nil {
+				return err
+			}
+			if top < 1 {
+				return usageErrorf("stats", "invalid top %d, expected a positive number", top)
+			}
+
+			source, err := app.Source()
+			if err != nil {
+				return err
+			}
+			options, save, err := flags.options(app, "stats")
+			if err != nil {
+				return err
+			}
+			// The whole library includes the Liked Songs
+			names := saved
+			if len(args) == 0 && names == "" {
+				names = "tracks"
+			}
+			selected, err := selectSaved(selectPlaylists(source, args), names, true)
+			if err != nil {
+				return usageErrorf("stats", "%s", err.Error())
+			}
+
+			// The statistics are collected through the export pipeline so
+			// that the tracks go through the same stages
+			collector := stats.NewCollector()
+			format := export.Format{
+				Name: "stats",
+				New:  func(io.Writer, export.Options) export.Exporter { return collector },
+			}
+			if err := export.Export(selected, format, io.Discard, options); err != nil {
+				return err
+			}
+			if err := save(); err != nil {
+				return err
+			}
+
+			title := "Library"
+			if len(args) == 1 && saved == "" {
+				playlists, err := selected.Playlists()
+				if err != nil {
+					return err
+				}
+				title = playlists[0].Name
+			}
+
+			return stats.Render(app.stdout, collector.Stats(top), title, output)
+		},
+	}
+}
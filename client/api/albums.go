@@ -0,0 +1,24 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxAlbumsPerRequest is the number of albums which can be fetched at once
+const MaxAlbumsPerRequest = 20
+
+// Albums returns the full albums, in the order of the ids
+func (c *Client) Albums(ids []string) ([]*Album, error) {
+	if len(ids) > MaxAlbumsPerRequest {
+		return nil, fmt.Errorf("cannot get more than %d albums at once", MaxAlbumsPerRequest)
+	}
+
+	var response struct {
+		Albums []*Album `json:"albums"`
+	}
+	err := c.get("/albums", url.Values{"ids": {strings.Join(ids, ",")}}, &response)
+
+	return response.Albums, err
+}
@@ -0,0 +1,25 @@
+package api
+
+import "testing"
+
+func TestAlbums(t *testing.T) {
+	t.Run("it should return the full albums", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/albums?ids=b1%2Cb2": `{"albums": [{"id": "b1", "label": "Label", "release_date_precision": "year", "external_ids": {"upc": "123"}}, null]}`,
+		})
+
+		albums, err := client.Albums([]string{"b1", "b2"})
+
+		if err != nil || len(albums) != 2 || albums[0].Label != "Label" || albums[0].ExternalIds.Upc != "123" || albums[1] != nil {
+			t.Errorf("Unexpected albums %+v (%v)", albums, err)
+		}
+	})
+
+	t.Run("it should refuse more than 20 ids", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{})
+
+		if _, err := client.Albums(make([]string, 21)); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
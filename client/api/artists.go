@@ -0,0 +1,24 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxArtistsPerRequest is the number of artists which can be fetched at once
+const MaxArtistsPerRequest = 50
+
+// Artists returns the full artists, in the order of the ids
+func (c *Client) Artists(ids []string) ([]*Artist, error) {
+	if len(ids) > MaxArtistsPerRequest {
+		return nil, fmt.Errorf("cannot get more than %d artists at once", MaxArtistsPerRequest)
+	}
+
+	var response struct {
+		Artists []*Artist `json:"artists"`
+	}
+	err := c.get("/artists", url.Values{"ids": {strings.Join(ids, ",")}}, &response)
+
+	return response.Artists, err
+}
@@ -0,0 +1,25 @@
+package api
+
+import "testing"
+
+func TestArtists(t *testing.T) {
+	t.Run("it should return the full artists", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/artists?ids=a1%2Ca2": `{"artists": [{"id": "a1", "genres": ["rock"], "popularity": 80}, null]}`,
+		})
+
+		artists, err := client.Artists([]string{"a1", "a2"})
+
+		if err != nil || len(artists) != 2 || artists[0].Genres[0] != "rock" || artists[1] != nil {
+			t.Errorf("Unexpected artists %+v (%v)", artists, err)
+		}
+	})
+
+	t.Run("it should refuse more than 50 ids", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{})
+
+		if _, err := client.Artists(make([]string, 51)); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
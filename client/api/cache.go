@@ -0,0 +1,415 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheExtension is the extension of the cached responses, only these files
+// are listed and removed from the cache directory
+const cacheExtension = ".cache.json"
+
+// userPlaylistsGroup holds the playlists of the current user, whose snapshot
+// ids change with every write to one of them
+const userPlaylistsGroup = "v1/me/playlists"
+
+// uncachedGroups answer differently to the same request, such as the
+// recommendations which are drawn anew every time
+var uncachedGroups = map[string]bool{"v1/recommendations": true}
+
+// Cache is a RoundTripper storing the successful GET responses on disk, keyed
+// by url and user. Responses are fresh for the max-age of their Cache-Control
+// header, or the ttl when they have none, then revalidated with their ETag.
+// The least recently used responses are evicted above maxSize bytes, and any
+// other request invalidates the responses of the resource it writes to.
+type Cache struct {
+	next      http.RoundTripper
+	directory string
+	user      string
+	ttl       time.Duration
+	maxSize   int64
+	now       func() time.Time
+}
+
+func NewCache(next http.RoundTripper, directory string, user string, ttl time.Duration, maxSize int64) *Cache {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Cache{
+		next:      next,
+		directory: directory,
+		user:      user,
+		ttl:       ttl,
+		maxSize:   maxSize,
+		now:       time.Now,
+	}
+}
+
+type cacheEntry struct {
+	Url      string      `json:"url"`
+	User     string      `json:"user"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+	Expires  time.Time   `json:"expires"`
+}
+
+func (c *Cache) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != http.MethodGet && request.Method != http.MethodHead {
+		response, err := c.next.RoundTrip(request)
+		if invalidateErr := c.invalidate(request.URL); invalidateErr != nil && err == nil {
+			response.Body.Close()
+			return nil, invalidateErr
+		}
+		return response, err
+	}
+	if request.Method != http.MethodGet || request.Header.Get("Range") != "" || uncachedGroups[cacheGroup(request.URL)] {
+		return c.next.RoundTrip(request)
+	}
+
+	path := c.path(request)
+	entry, err := c.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry != nil && c.now().Before(entry.Expires) {
+		c.touch(path)
+		return entry.response(request), nil
+	}
+
+	etag := ""
+	if entry != nil {
+		etag = entry.Header.Get("ETag")
+	}
+	if etag != "" {
+		request = request.Clone(request.Context())
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := c.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotModified && etag != "" {
+		response.Body.Close()
+
+		for name, values := range response.Header {
+			entry.Header[name] = values
+		}
+		entry.Expires, _ = c.expires(entry.Header)
+		if err := c.store(path, entry); err != nil {
+			return nil, err
+		}
+
+		return entry.response(request), nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return response, nil
+	}
+
+	expires, cacheable := c.expires(response.Header)
+	if !cacheable {
+		return response, nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = c.store(path, &cacheEntry{
+		Url:      request.URL.String(),
+		User:     c.user,
+		Status:   response.StatusCode,
+		Header:   response.Header.Clone(),
+		Body:     body,
+		StoredAt: c.now(),
+		Expires:  expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// expires returns when a response with the given headers stops being fresh,
+// and whether it can be stored at all
+func (c *Cache) expires(header http.Header) (time.Time, bool) {
+	lifetime := c.ttl
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(directive)), "=")
+		switch name {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			lifetime = 0
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				lifetime = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	// Without validator a stale response is useless
+	if lifetime <= 0 && header.Get("ETag") == "" {
+		return time.Time{}, false
+	}
+
+	return c.now().Add(lifetime), true
+}
+
+// path returns the file of a response, prefixed by its user then its group
+// so that the responses of either are removed together
+func (c *Cache) path(request *http.Request) string {
+	key := sha256.Sum256([]byte(c.user + "\n" + request.URL.String()))
+	return filepath.Join(c.directory, c.groupPrefix(cacheGroup(request.URL))+hex.EncodeToString(key[:])+cacheExtension)
+}
+
+func (c *Cache) groupPrefix(group string) string {
+	key := sha256.Sum256([]byte(c.user + "\n" + group))
+	return userPrefix(c.user) + hex.EncodeToString(key[:8]) + "-"
+}
+
+func userPrefix(user string) string {
+	key := sha256.Sum256([]byte(user))
+	return hex.EncodeToString(key[:8]) + "-"
+}
+
+// cacheGroup returns the resource a response belongs to: the details, items,
+// images and followers of a playlist form a group, any other path its own
+func cacheGroup(location *url.URL) string {
+	segments := strings.Split(strings.Trim(location.Path, "/"), "/")
+	if len(segments) > 3 && segments[1] == "playlists" {
+		segments = segments[:3]
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// invalidate removes the responses of the group written by a request, along
+// with the playlists of the user when a playlist is written
+func (c *Cache) invalidate(location *url.URL) error {
+	groups := []string{cacheGroup(location)}
+	if strings.Contains(groups[0], "playlists") && groups[0] != userPlaylistsGroup {
+		groups = append(groups, userPlaylistsGroup)
+	}
+
+	for _, group := range groups {
+		paths, err := filepath.Glob(filepath.Join(c.directory, c.groupPrefix(group)+"*"+cacheExtension))
+		if err != nil {
+			return fmt.Errorf("failed to list cache entries: %w", err)
+		}
+
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to invalidate cache entry: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Cache) load(path string) (*cacheEntry, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		// A corrupted entry is refetched
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (c *Cache) store(path string, entry *cacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.directory, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	c.touch(path)
+
+	return c.evict()
+}
+
+// touch marks the entry as recently used through its modification time
+func (c *Cache) touch(path string) {
+	now := c.now()
+	os.Chtimes(path, now, now)
+}
+
+// evict removes the least recently used entries until the cache fits maxSize
+func (c *Cache) evict() error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+
+	files, err := cacheFiles(c.directory)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, file := range files {
+		size += file.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, file := range files {
+		if size <= c.maxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.directory, file.Name())); err != nil {
+			return fmt.Errorf("failed to evict cache entry: %w", err)
+		}
+		size -= file.Size()
+	}
+
+	return nil
+}
+
+func (e *cacheEntry) response(request *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("X-Cache", "HIT")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       request,
+	}
+}
+
+type CacheStats struct {
+	Entries int       `json:"entries"`
+	Size    int64     `json:"size"`
+	Oldest  time.Time `json:"oldest"`
+	Newest  time.Time `json:"newest"`
+}
+
+// ReadCacheStats returns the number and size of the cached responses, and
+// when the oldest and newest were last used
+func ReadCacheStats(directory string) (*CacheStats, error) {
+	files, err := cacheFiles(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CacheStats{Entries: len(files)}
+	for _, file := range files {
+		stats.Size += file.Size()
+		if stats.Oldest.IsZero() || file.ModTime().Before(stats.Oldest) {
+			stats.Oldest = file.ModTime()
+		}
+		if file.ModTime().After(stats.Newest) {
+			stats.Newest = file.ModTime()
+		}
+	}
+
+	return stats, nil
+}
+
+// ClearCache removes every cached response and returns how many were removed
+func ClearCache(directory string) (int, error) {
+	files, err := cacheFiles(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, file := range files {
+		if err := os.Remove(filepath.Join(directory, file.Name())); err != nil {
+			return i, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+	}
+
+	return len(files), nil
+}
+
+// ClearUserCache removes the responses cached for a user and returns how many
+// were removed
+func ClearUserCache(directory string, user string) (int, error) {
+	files, err := cacheFiles(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if !strings.HasPrefix(file.Name(), userPrefix(user)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(directory, file.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func cacheFiles(directory string) ([]fs.FileInfo, error) {
+	entries, err := os.ReadDir(directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var files []fs.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), cacheExtension) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache entry: %w", err)
+		}
+		files = append(files, info)
+	}
+
+	return files, nil
+}
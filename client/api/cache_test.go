@@ -0,0 +1,230 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("it should answer fresh responses from the cache", func(t *testing.T) {
+		// Given a server answering with a max-age
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			response := jsonResponse(http.StatusOK, `{"id": "user"}`)
+			response.Header.Set("Cache-Control", "private, max-age=60")
+			return response
+		})
+		client := NewClient(&http.Client{Transport: NewCache(next, t.TempDir(), "user", 0, 0)}, "token")
+
+		// When getting the same resource twice
+		client.CurrentUser()
+		user, err := client.CurrentUser()
+
+		// Then the server should be called once
+		if err != nil || user.Id != "user" || calls != 1 {
+			t.Errorf("Expected a single call, got %d calls (%v)", calls, err)
+		}
+	})
+
+	t.Run("it should revalidate stale responses with their etag", func(t *testing.T) {
+		// Given a server answering 304 when the etag matches
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				return jsonResponse(http.StatusNotModified, "")
+			}
+			response := jsonResponse(http.StatusOK, "content")
+			response.Header.Set("Cache-Control", "max-age=0")
+			response.Header.Set("ETag", `"v1"`)
+			return response
+		})
+		cache := NewCache(next, t.TempDir(), "user", time.Hour, 0)
+
+		// When getting the same resource twice
+		get(t, cache, "https://api.spotify.com/v1/me")
+		body, header := get(t, cache, "https://api.spotify.com/v1/me")
+
+		// Then the second response should be revalidated and served from the cache
+		if calls != 2 || body != "content" || header.Get("X-Cache") != "HIT" {
+			t.Errorf("Unexpected response '%s' after %d calls", body, calls)
+		}
+	})
+
+	t.Run("it should key the responses by user", func(t *testing.T) {
+		// Given two users sharing a cache directory
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			return jsonResponse(http.StatusOK, "content")
+		})
+		directory := t.TempDir()
+
+		// When both get the same url
+		get(t, NewCache(next, directory, "first", time.Hour, 0), "https://api.spotify.com/v1/me")
+		get(t, NewCache(next, directory, "second", time.Hour, 0), "https://api.spotify.com/v1/me")
+
+		// Then the server should be called for each
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("it should not store no-store responses", func(t *testing.T) {
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			response := jsonResponse(http.StatusOK, "content")
+			response.Header.Set("Cache-Control", "no-store")
+			return response
+		})
+		cache := NewCache(next, t.TempDir(), "user", time.Hour, 0)
+
+		get(t, cache, "https://api.spotify.com/v1/me")
+		get(t, cache, "https://api.spotify.com/v1/me")
+
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("it should evict the least recently used responses", func(t *testing.T) {
+		// Given a cache holding a single response
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			return jsonResponse(http.StatusOK, "content")
+		})
+		directory := t.TempDir()
+		cache := NewCache(next, directory, "user", time.Hour, 250)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		// When storing two responses
+		get(t, cache, "https://api.spotify.com/v1/first")
+		now = now.Add(time.Minute)
+		get(t, cache, "https://api.spotify.com/v1/second")
+
+		// Then only the last one should be kept
+		stats, _ := ReadCacheStats(directory)
+		if stats.Entries != 1 || stats.Size > 250 {
+			t.Fatalf("Unexpected stats %+v", stats)
+		}
+		get(t, cache, "https://api.spotify.com/v1/second")
+		if calls != 2 {
+			t.Errorf("Expected the second response to be cached, got %d calls", calls)
+		}
+	})
+
+	t.Run("it should not store the recommendations", func(t *testing.T) {
+		// Given a server answering with a max-age
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			response := jsonResponse(http.StatusOK, "content")
+			response.Header.Set("Cache-Control", "max-age=60")
+			return response
+		})
+		cache := NewCache(next, t.TempDir(), "user", time.Hour, 0)
+
+		// When getting the same recommendations twice
+		get(t, cache, "https://api.spotify.com/v1/recommendations?seed_genres=house")
+		get(t, cache, "https://api.spotify.com/v1/recommendations?seed_genres=house")
+
+		// Then the server should be called twice
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("it should invalidate the responses of a written playlist", func(t *testing.T) {
+		// Given cached responses of two playlists and of the user's playlists
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			return jsonResponse(http.StatusOK, "content")
+		})
+		cache := NewCache(next, t.TempDir(), "user", time.Hour, 0)
+		urls := []string{
+			"https://api.spotify.com/v1/playlists/p1",
+			"https://api.spotify.com/v1/playlists/p1/tracks?limit=100",
+			"https://api.spotify.com/v1/playlists/p2/tracks?limit=100",
+			"https://api.spotify.com/v1/me/playlists?limit=50",
+		}
+		for _, url := range urls {
+			get(t, cache, url)
+		}
+
+		// When removing items from the first playlist
+		request, _ := http.NewRequest(http.MethodDelete, "https://api.spotify.com/v1/playlists/p1/tracks", nil)
+		cache.RoundTrip(request)
+
+		// Then only the second playlist should still be cached
+		calls = 0
+		for _, url := range urls {
+			if _, header := get(t, cache, url); (header.Get("X-Cache") == "HIT") != strings.Contains(url, "p2") {
+				t.Errorf("Unexpected cache status of %s: %s", url, header.Get("X-Cache"))
+			}
+		}
+		if calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("it should clear the cached responses", func(t *testing.T) {
+		// Given a cached response
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			return jsonResponse(http.StatusOK, "content")
+		})
+		directory := t.TempDir()
+		get(t, NewCache(next, directory, "user", time.Hour, 0), "https://api.spotify.com/v1/me")
+
+		// When clearing the cache
+		removed, err := ClearCache(directory)
+
+		// Then the cache should be empty
+		stats, _ := ReadCacheStats(directory)
+		if err != nil || removed != 1 || stats.Entries != 0 {
+			t.Errorf("Unexpected %d removed, stats %+v (%v)", removed, stats, err)
+		}
+	})
+
+	t.Run("it should clear the responses of a user only", func(t *testing.T) {
+		// Given responses cached for two users
+		calls := 0
+		next := cacheServer(&calls, func(req *http.Request) *http.Response {
+			return jsonResponse(http.StatusOK, "content")
+		})
+		directory := t.TempDir()
+		get(t, NewCache(next, directory, "first", time.Hour, 0), "https://api.spotify.com/v1/me")
+		get(t, NewCache(next, directory, "first", time.Hour, 0), "https://api.spotify.com/v1/me/playlists")
+		get(t, NewCache(next, directory, "second", time.Hour, 0), "https://api.spotify.com/v1/me")
+
+		// When clearing the responses of the first user
+		removed, err := ClearUserCache(directory, "first")
+
+		// Then only the responses of the second user should be left
+		stats, _ := ReadCacheStats(directory)
+		if err != nil || removed != 2 || stats.Entries != 1 {
+			t.Errorf("Unexpected %d removed, stats %+v (%v)", removed, stats, err)
+		}
+	})
+}
+
+// Helpers
+func cacheServer(calls *int, handle func(req *http.Request) *http.Response) http.RoundTripper {
+	return &mockRoundTripper{func(req *http.Request) (*http.Response, error) {
+		*calls++
+		return handle(req), nil
+	}}
+}
+
+func get(t *testing.T, cache *Cache, url string) (string, http.Header) {
+	request, _ := http.NewRequest(http.MethodGet, url, nil)
+	response, err := cache.RoundTrip(request)
+	if err != nil {
+		t.Fatalf("RoundTrip returned an error: %s", err.Error())
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(response.Body)
+	return string(body), response.Header
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	baseUrl    = "https://api.spotify.com/v1"
+	maxRetries = 3
+)
+
+// Client is a minimal client of the Spotify Web API
+type Client struct {
+	client  *http.Client
+	baseUrl string
+	token   string
+	sleep   func(time.Duration)
+}
+
+func NewClient(client *http.Client, token string) *Client {
+	return &Client{
+		client:  client,
+		baseUrl: baseUrl,
+		token:   token,
+		sleep:   time.Sleep,
+	}
+}
+
+// Error is the regular error object returned by the Web API
+type Error struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("spotify api error %d: %s", e.Status, e.Message)
+}
+
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+	Total int    `json:"total"`
+}
+
+func (c *Client) get(path string, query url.Values, out any) error {
+	return c.do(http.MethodGet, path, query, nil, out)
+}
+
+// do sends a request to the given path, or to the given url when it is
+// absolute, and decodes the json response into out when not nil.
+// Rate limited requests are retried after the delay asked by the server.
+func (c *Client) do(method string, path string, query url.Values, body any, out any) error {
+	endpoint := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		endpoint = c.baseUrl + path
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		payload = encoded
+	}
+
+	for attempt := 0; ; attempt++ {
+		request, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		request.Header.Set("Authorization", "Bearer "+c.token)
+		if body != nil {
+			request.Header.Set("Content-Type", "application/json")
+		}
+
+		response, err := c.client.Do(request)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests && attempt < maxRetries {
+			response.Body.Close()
+			c.sleep(retryAfter(response))
+			continue
+		}
+
+		return decodeResponse(response, out)
+	}
+}
+
+func decodeResponse(response *http.Response, out any) error {
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var wrapper struct {
+			Error Error `json:"error"`
+		}
+		if json.Unmarshal(content, &wrapper) != nil || wrapper.Error.Status == 0 {
+			wrapper.Error = Error{Status: response.StatusCode, Message: http.StatusText(response.StatusCode)}
+		}
+
+		return &wrapper.Error
+	}
+
+	if out == nil || len(content) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	return nil
+}
+
+func retryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(response.Header.Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		seconds = 1
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// paginate walks every page starting from path, following the next links
+func paginate[T any](c *Client, path string, query url.Values, handle func(T) error) error {
+	next := path
+	for next != "" {
+		var page Page[T]
+		if err := c.get(next, query, &page); err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			if err := handle(item); err != nil {
+				return err
+			}
+		}
+
+		// The next link already carries the query
+		next = page.Next
+		query = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// To mock the http client, we mock the underlying roundtripper
+// mockRoundTripper implements http.RoundTripper for testing purposes
+type mockRoundTripper struct {
+	roundTripFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m.roundTripFunc(req)
+}
+
+func TestClient(t *testing.T) {
+	t.Run("it should send the bearer token and decode the response", func(t *testing.T) {
+		// Given a round tripper checking the authorization header
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("Expected bearer token, got '%s'", req.Header.Get("Authorization"))
+			}
+			if req.URL.String() != "https://api.spotify.com/v1/me" {
+				t.Errorf("Unexpected url %s", req.URL.String())
+			}
+
+			return jsonResponse(http.StatusOK, `{"id": "user-id", "display_name": "User"}`), nil
+		})
+
+		// When getting the current user
+		user, err := client.CurrentUser()
+
+		// Then the user should be decoded
+		if err != nil {
+			t.Fatalf("CurrentUser returned an error: %s", err.Error())
+		}
+		if user.Id != "user-id" || user.DisplayName != "User" {
+			t.Errorf("Unexpected user %+v", user)
+		}
+	})
+
+	t.Run("it should return the api error object", func(t *testing.T) {
+		// Given a round tripper returning an error object
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusUnauthorized, `{"error": {"status": 401, "message": "The access token expired"}}`), nil
+		})
+
+		// When getting the current user
+		_, err := client.CurrentUser()
+
+		// Then the api error should be returned
+		var apiError *Error
+		if !errors.As(err, &apiError) || apiError.Status != 401 {
+			t.Fatalf("Expected an api error, got %v", err)
+		}
+		if err.Error() != "spotify api error 401: The access token expired" {
+			t.Errorf("Unexpected error message '%s'", err.Error())
+		}
+	})
+
+	t.Run("it should build an api error from the status when the body is not an error object", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusBadGateway, "not json"), nil
+		})
+
+		_, err := client.CurrentUser()
+
+		var apiError *Error
+		if !errors.As(err, &apiError) || apiError.Status != http.StatusBadGateway {
+			t.Errorf("Expected a 502 api error, got %v", err)
+		}
+	})
+
+	t.Run("it should retry rate limited requests after the requested delay", func(t *testing.T) {
+		// Given a round tripper rate limiting the first request
+		calls := 0
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				response := jsonResponse(http.StatusTooManyRequests, "")
+				response.Header.Set("Retry-After", "7")
+				return response, nil
+			}
+
+			return jsonResponse(http.StatusOK, `{"id": "user-id"}`), nil
+		})
+
+		// and a recorded sleep
+		var slept time.Duration
+		client.sleep = func(d time.Duration) { slept += d }
+
+		// When getting the current user
+		_, err := client.CurrentUser()
+
+		// Then the request should have been retried
+		if err != nil {
+			t.Fatalf("CurrentUser returned an error: %s", err.Error())
+		}
+		if calls != 2 || slept != 7*time.Second {
+			t.Errorf("Expected a retry after 7s, got %d calls and %s", calls, slept)
+		}
+	})
+
+	t.Run("it should give up after too many rate limited attempts", func(t *testing.T) {
+		calls := 0
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(http.StatusTooManyRequests, ""), nil
+		})
+
+		_, err := client.CurrentUser()
+
+		if err == nil || calls != maxRetries+1 {
+			t.Errorf("Expected an error after %d calls, got %d calls (%v)", maxRetries+1, calls, err)
+		}
+	})
+}
+
+// Helpers
+func createClient(roundTripFunc func(req *http.Request) (*http.Response, error)) *Client {
+	client := NewClient(&http.Client{Transport: &mockRoundTripper{roundTripFunc}}, "token")
+	client.sleep = func(time.Duration) {}
+
+	return client
+}
+
+// createRoutedClient answers every request with the body registered for its url
+func createRoutedClient(t *testing.T, routes map[string]string) *Client {
+	return createClient(func(req *http.Request) (*http.Response, error) {
+		body, ok := routes[req.URL.String()]
+		if !ok {
+			t.Errorf("Unexpected request to %s", req.URL.String())
+			return jsonResponse(http.StatusNotFound, ""), nil
+		}
+
+		return jsonResponse(http.StatusOK, body), nil
+	})
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     make(http.Header),
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const pageLimit = "50"
+
+// MarketFromToken is the market of the country of the current user
+const MarketFromToken = "from_token"
+
+func (c *Client) CurrentUser() (*User, error) {
+	var user User
+	if err := c.get("/me", nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CurrentUserPlaylists streams the playlists owned or followed by the user
+func (c *Client) CurrentUserPlaylists(handle func(SimplifiedPlaylist) error) error {
+	return paginate(c, "/me/playlists", url.Values{"limit": {pageLimit}}, handle)
+}
+
+// PlaylistItems streams the items of a playlist, episodes being returned as
+// such rather than in the legacy track format
+func (c *Client) PlaylistItems(playlistId string, handle func(PlaylistItem) error) error {
+	return c.PlaylistItemsInMarket(playlistId, "", handle)
+}
+
+// PlaylistItemsInMarket streams the items of a playlist as available in the
+// market, such as MarketFromToken, relinking the tracks which are not. An
+// empty market returns the items as they were added.
+func (c *Client) PlaylistItemsInMarket(playlistId string, market string, handle func(PlaylistItem) error) error {
+	path := fmt.Sprintf("/playlists/%s/tracks", url.PathEscape(playlistId))
+	query := url.Values{"limit": {"100"}, "additional_types": {"track,episode"}}
+	if market != "" {
+		query.Set("market", market)
+	}
+	return paginate(c, path, query, handle)
+}
+
+func (c *Client) SavedTracks(handle func(SavedTrack) error) error {
+	return paginate(c, "/me/tracks", url.Values{"limit": {pageLimit}}, handle)
+}
+
+func (c *Client) SavedAlbums(handle func(SavedAlbum) error) error {
+	return paginate(c, "/me/albums", url.Values{"limit": {pageLimit}}, handle)
+}
+
+// AlbumTracks streams the tracks of an album, without their album
+func (c *Client) AlbumTracks(albumId string, handle func(Track) error) error {
+	path := fmt.Sprintf("/albums/%s/tracks", url.PathEscape(albumId))
+	return paginate(c, path, url.Values{"limit": {pageLimit}}, handle)
+}
+
+func (c *Client) SavedShows(handle func(SavedShow) error) error {
+	return paginate(c, "/me/shows", url.Values{"limit": {pageLimit}}, handle)
+}
+
+func (c *Client) SavedEpisodes(handle func(SavedEpisode) error) error {
+	return paginate(c, "/me/episodes", url.Values{"limit": {pageLimit}}, handle)
+}
+
+func (c *Client) FollowedArtists(handle func(Artist) error) error {
+	query := url.Values{"type": {"artist"}, "limit": {pageLimit}}
+	next := "/me/following"
+	for next != "" {
+		// Followed artists are wrapped and paged by cursor
+		var response struct {
+			Artists Page[Artist] `json:"artists"`
+		}
+		if err := c.get(next, query, &response); err != nil {
+			return err
+		}
+
+		for _, artist := range response.Artists.Items {
+			if err := handle(artist); err != nil {
+				return err
+			}
+		}
+
+		next = response.Artists.Next
+		query = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestLibrary(t *testing.T) {
+	t.Run("it should follow the next links of the playlist pages", func(t *testing.T) {
+		// Given two pages of playlists
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/me/playlists?limit=50": `{
+				"items": [{"id": "p1", "snapshot_id": "s1", "tracks": {"total": 3}}],
+				"next": "https://api.spotify.com/v1/me/playlists?offset=1&limit=50"
+			}`,
+			"https://api.spotify.com/v1/me/playlists?offset=1&limit=50": `{
+				"items": [{"id": "p2", "owner": {"id": "owner"}}],
+				"next": null
+			}`,
+		})
+
+		// When listing the playlists
+		var playlists []SimplifiedPlaylist
+		err := client.CurrentUserPlaylists(func(playlist SimplifiedPlaylist) error {
+			playlists = append(playlists, playlist)
+			return nil
+		})
+
+		// Then every page should be walked
+		if err != nil {
+			t.Fatalf("CurrentUserPlaylists returned an error: %s", err.Error())
+		}
+		if len(playlists) != 2 || playlists[0].Tracks.Total != 3 || playlists[1].Owner.Id != "owner" {
+			t.Errorf("Unexpected playlists %+v", playlists)
+		}
+	})
+
+	t.Run("it should decode playlist items with unavailable tracks", func(t *testing.T) {
+		// Given a page with a removed track
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [
+				{"added_at": "2024-01-02T03:04:05Z", "added_by": {"id": "adder"}, "track": {"id": "t1", "external_ids": {"isrc": "ISRC"}}},
+				{"added_at": null, "track": null}
+			]}`,
+		})
+
+		// When streaming the items
+		var items []PlaylistItem
+		err := client.PlaylistItems("p1", func(item PlaylistItem) error {
+			items = append(items, item)
+			return nil
+		})
+
+		// Then both items should be returned
+		if err != nil {
+			t.Fatalf("PlaylistItems returned an error: %s", err.Error())
+		}
+		track, ok := items[0].Item.(*Track)
+		if len(items) != 2 || !ok || track.ExternalIds.Isrc != "ISRC" || items[0].AddedBy.Id != "adder" {
+			t.Fatalf("Unexpected items %+v", items)
+		}
+		if _, ok := items[1].Item.(Unavailable); !ok || !items[1].AddedAt.IsZero() {
+			t.Errorf("Expected an unavailable item, got %+v", items[1])
+		}
+	})
+
+	t.Run("it should decode episodes and local files", func(t *testing.T) {
+		// Given a page with an episode and a local file
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100": `{"items": [
+				{"is_local": false, "track": {"type": "episode", "id": "e1", "name": "Episode", "show": {"id": "s1", "name": "Show"}}},
+				{"is_local": true, "track": {"type": "track", "id": null, "uri": "spotify:local:Artist:Album:Title:180", "name": "Title", "is_local": true, "album": {"id": null, "name": "Album"}}}
+			]}`,
+		})
+
+		// When streaming the items
+		var items []PlaylistItem
+		err := client.PlaylistItems("p1", func(item PlaylistItem) error {
+			items = append(items, item)
+			return nil
+		})
+
+		// Then each item should be decoded according to its type
+		if err != nil || len(items) != 2 {
+			t.Fatalf("Unexpected items %+v, %v", items, err)
+		}
+		if episode, ok := items[0].Item.(*Episode); !ok || episode.Show.Name != "Show" {
+			t.Errorf("Expected an episode, got %+v", items[0].Item)
+		}
+		if local, ok := items[1].Item.(*LocalFile); !ok || local.Id != "" || local.Album.Name != "Album" {
+			t.Errorf("Expected a local file, got %+v", items[1].Item)
+		}
+	})
+
+	t.Run("it should decode the availability of the items in a market", func(t *testing.T) {
+		// Given a page with a relinked and a restricted track
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/playlists/p1/tracks?additional_types=track%2Cepisode&limit=100&market=from_token": `{"items": [
+				{"track": {"id": "t2", "is_playable": true, "linked_from": {"id": "t1", "uri": "spotify:track:t1"}}},
+				{"track": {"id": "t3", "is_playable": false, "restrictions": {"reason": "market"}}}
+			]}`,
+		})
+
+		// When streaming the items in the market of the user
+		var tracks []*Track
+		err := client.PlaylistItemsInMarket("p1", MarketFromToken, func(item PlaylistItem) error {
+			tracks = append(tracks, item.Item.(*Track))
+			return nil
+		})
+
+		// Then the relinking and the restriction should be decoded
+		if err != nil || len(tracks) != 2 {
+			t.Fatalf("Unexpected tracks %+v, %v", tracks, err)
+		}
+		if tracks[0].LinkedFrom == nil || tracks[0].LinkedFrom.Id != "t1" || !*tracks[0].IsPlayable {
+			t.Errorf("Expected a relinked track, got %+v", tracks[0])
+		}
+		if *tracks[1].IsPlayable || tracks[1].Restrictions == nil || tracks[1].Restrictions.Reason != "market" {
+			t.Errorf("Expected a restricted track, got %+v", tracks[1])
+		}
+	})
+
+	t.Run("it should walk the cursor pages of the followed artists", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/me/following?limit=50&type=artist": `{"artists": {
+				"items": [{"id": "a1", "genres": ["rock"]}],
+				"next": "https://api.spotify.com/v1/me/following?type=artist&after=a1&limit=50"
+			}}`,
+			"https://api.spotify.com/v1/me/following?type=artist&after=a1&limit=50": `{"artists": {
+				"items": [{"id": "a2", "followers": {"total": 10}}]
+			}}`,
+		})
+
+		var artists []Artist
+		err := client.FollowedArtists(func(artist Artist) error {
+			artists = append(artists, artist)
+			return nil
+		})
+
+		if err != nil {
+			t.Fatalf("FollowedArtists returned an error: %s", err.Error())
+		}
+		if len(artists) != 2 || artists[0].Genres[0] != "rock" || artists[1].Followers.Total != 10 {
+			t.Errorf("Unexpected artists %+v", artists)
+		}
+	})
+	t.Run("it should decode the saved episodes with their show", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/me/episodes?limit=50": `{"items": [{
+				"added_at": "2024-01-02T03:04:05Z",
+				"episode": {"id": "e1", "name": "Episode", "duration_ms": 1800000, "show": {"id": "s1", "name": "Show", "publisher": "Publisher"}}
+			}]}`,
+		})
+
+		var episodes []SavedEpisode
+		err := client.SavedEpisodes(func(saved SavedEpisode) error {
+			episodes = append(episodes, saved)
+			return nil
+		})
+
+		if err != nil || len(episodes) != 1 || episodes[0].AddedAt.IsZero() || episodes[0].Episode.Show.Publisher != "Publisher" {
+			t.Errorf("Unexpected episodes %+v (%v)", episodes, err)
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type User struct {
+	Id          string `json:"id"`
+	Uri         string `json:"uri"`
+	DisplayName string `json:"display_name"`
+	Country     string `json:"country"`
+	Product     string `json:"product"`
+}
+
+type SimplifiedArtist struct {
+	Id   string `json:"id"`
+	Uri  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type Artist struct {
+	SimplifiedArtist
+	Genres     []string `json:"genres"`
+	Popularity int      `json:"popularity"`
+	Followers  struct {
+		Total int `json:"total"`
+	} `json:"followers"`
+}
+
+type SimplifiedAlbum struct {
+	Id                   string             `json:"id"`
+	Uri                  string             `json:"uri"`
+	Name                 string             `json:"name"`
+	AlbumType            string             `json:"album_type"`
+	ReleaseDate          string             `json:"release_date"`
+	ReleaseDatePrecision string             `json:"release_date_precision"`
+	TotalTracks          int                `json:"total_tracks"`
+	Artists              []SimplifiedArtist `json:"artists"`
+}
+
+type Copyright struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+type Album struct {
+	SimplifiedAlbum
+	// Tracks holds the first page of the tracks of the album
+	Tracks      Page[Track] `json:"tracks"`
+	Label       string      `json:"label"`
+	Genres      []string    `json:"genres"`
+	Popularity  int         `json:"popularity"`
+	Copyrights  []Copyright `json:"copyrights"`
+	ExternalIds struct {
+		Upc string `json:"upc"`
+	} `json:"external_ids"`
+}
+
+type Track struct {
+	Id          string             `json:"id"`
+	Uri         string             `json:"uri"`
+	Name        string             `json:"name"`
+	Artists     []SimplifiedArtist `json:"artists"`
+	Album       SimplifiedAlbum    `json:"album"`
+	DurationMs  int                `json:"duration_ms"`
+	Explicit    bool               `json:"explicit"`
+	Popularity  int                `json:"popularity"`
+	TrackNumber int                `json:"track_number"`
+	DiscNumber  int                `json:"disc_number"`
+	IsLocal     bool               `json:"is_local"`
+	ExternalIds struct {
+		Isrc string `json:"isrc"`
+	} `json:"external_ids"`
+	// Set only when a market is requested: IsPlayable tells whether the track
+	// can be played there, LinkedFrom is the requested track when Spotify
+	// relinked it to a playable one and Restrictions explains why it cannot
+	IsPlayable   *bool         `json:"is_playable"`
+	LinkedFrom   *LinkedTrack  `json:"linked_from"`
+	Restrictions *Restrictions `json:"restrictions"`
+}
+
+type LinkedTrack struct {
+	Id  string `json:"id"`
+	Uri string `json:"uri"`
+}
+
+// Restrictions holds the reason why content is not playable: market,
+// product or explicit
+type Restrictions struct {
+	Reason string `json:"reason"`
+}
+
+type SimplifiedPlaylist struct {
+	Id            string `json:"id"`
+	Uri           string `json:"uri"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Owner         User   `json:"owner"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+	SnapshotId    string `json:"snapshot_id"`
+	Tracks        struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+}
+
+type PlaylistItem struct {
+	AddedAt time.Time `json:"added_at"`
+	AddedBy User      `json:"added_by"`
+	IsLocal bool      `json:"is_local"`
+	// Item is decoded from the track field of the response according to its type
+	Item Item `json:"-"`
+}
+
+// Item is the content of a playlist item, one of *Track, *Episode,
+// *LocalFile or Unavailable
+type Item interface {
+	item()
+}
+
+func (*Track) item()      {}
+func (*Episode) item()    {}
+func (*LocalFile) item()  {}
+func (Unavailable) item() {}
+
+// LocalFile is a file of the computer of the user added to a playlist, it
+// has a spotify:local uri but no id, nor album or artist ids
+type LocalFile struct {
+	Track
+}
+
+// Unavailable is an item which was removed from Spotify
+type Unavailable struct{}
+
+func (p *PlaylistItem) UnmarshalJSON(data []byte) error {
+	type plain PlaylistItem
+	var raw struct {
+		plain
+		Track json.RawMessage `json:"track"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*p = PlaylistItem(raw.plain)
+	item, err := decodeItem(raw.Track, p.IsLocal)
+	p.Item = item
+	return err
+}
+
+func decodeItem(data json.RawMessage, isLocal bool) (Item, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return Unavailable{}, nil
+	}
+
+	var kind struct {
+		Type    string `json:"type"`
+		IsLocal bool   `json:"is_local"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+
+	var item Item
+	switch {
+	case kind.Type == "episode":
+		item = &Episode{}
+	case isLocal || kind.IsLocal:
+		item = &LocalFile{}
+	default:
+		item = &Track{}
+	}
+
+	return item, json.Unmarshal(data, item)
+}
+
+type SavedTrack struct {
+	AddedAt time.Time `json:"added_at"`
+	Track   Track     `json:"track"`
+}
+
+type SavedAlbum struct {
+	AddedAt time.Time `json:"added_at"`
+	Album   Album     `json:"album"`
+}
+
+type SimplifiedShow struct {
+	Id            string `json:"id"`
+	Uri           string `json:"uri"`
+	Name          string `json:"name"`
+	Publisher     string `json:"publisher"`
+	Description   string `json:"description"`
+	Explicit      bool   `json:"explicit"`
+	TotalEpisodes int    `json:"total_episodes"`
+}
+
+type Episode struct {
+	Id          string `json:"id"`
+	Uri         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	DurationMs  int    `json:"duration_ms"`
+	ReleaseDate string `json:"release_date"`
+	Explicit    bool   `json:"explicit"`
+	// Show is not set for the episodes listed by show
+	Show *SimplifiedShow `json:"show"`
+}
+
+type SavedShow struct {
+	AddedAt time.Time      `json:"added_at"`
+	Show    SimplifiedShow `json:"show"`
+}
+
+type SavedEpisode struct {
+	AddedAt time.Time `json:"added_at"`
+	Episode Episode   `json:"episode"`
+}
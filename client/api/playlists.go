@@ -0,0 +1,104 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// MaxItemsPerRequest is the number of items which can be added or removed
+// from a playlist in a single request
+const MaxItemsPerRequest = 100
+
+type PlaylistDetails struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+}
+
+type snapshotResponse struct {
+	SnapshotId string `json:"snapshot_id"`
+}
+
+func (c *Client) CreatePlaylist(userId string, details PlaylistDetails) (*SimplifiedPlaylist, error) {
+	var playlist SimplifiedPlaylist
+	path := fmt.Sprintf("/users/%s/playlists", url.PathEscape(userId))
+	if err := c.do(http.MethodPost, path, nil, details, &playlist); err != nil {
+		return nil, err
+	}
+
+	return &playlist, nil
+}
+
+// AddItems appends up to MaxItemsPerRequest uris to the playlist and returns
+// its new snapshot id
+func (c *Client) AddItems(playlistId string, uris []string) (string, error) {
+	return c.addItems(playlistId, uris, nil)
+}
+
+// InsertItems inserts up to MaxItemsPerRequest uris before the item at the
+// position and returns the new snapshot id of the playlist
+func (c *Client) InsertItems(playlistId string, uris []string, position int) (string, error) {
+	return c.addItems(playlistId, uris, &position)
+}
+
+func (c *Client) addItems(playlistId string, uris []string, position *int) (string, error) {
+	if len(uris) > MaxItemsPerRequest {
+		return "", fmt.Errorf("cannot add more than %d items at once", MaxItemsPerRequest)
+	}
+
+	body := struct {
+		Uris     []string `json:"uris"`
+		Position *int     `json:"position,omitempty"`
+	}{uris, position}
+
+	var response snapshotResponse
+	path := fmt.Sprintf("/playlists/%s/tracks", url.PathEscape(playlistId))
+	err := c.do(http.MethodPost, path, nil, body, &response)
+
+	return response.SnapshotId, err
+}
+
+// ItemPositions identifies the occurrences of an item in a playlist
+type ItemPositions struct {
+	Uri       string `json:"uri"`
+	Positions []int  `json:"positions"`
+}
+
+// RemoveItems removes the given occurrences from the playlist version
+// identified by snapshotId, so that positions refer to the expected version,
+// and returns the new snapshot id
+func (c *Client) RemoveItems(playlistId string, snapshotId string, items []ItemPositions) (string, error) {
+	if len(items) > MaxItemsPerRequest {
+		return "", fmt.Errorf("cannot remove more than %d items at once", MaxItemsPerRequest)
+	}
+
+	body := struct {
+		Tracks     []ItemPositions `json:"tracks"`
+		SnapshotId string          `json:"snapshot_id,omitempty"`
+	}{items, snapshotId}
+
+	var response snapshotResponse
+	path := fmt.Sprintf("/playlists/%s/tracks", url.PathEscape(playlistId))
+	err := c.do(http.MethodDelete, path, nil, body, &response)
+
+	return response.SnapshotId, err
+}
+
+// ReorderItems moves rangeLength items starting at rangeStart before the
+// item at insertBefore, positions refer to the playlist before the move
+func (c *Client) ReorderItems(playlistId string, snapshotId string, rangeStart int, insertBefore int, rangeLength int) (string, error) {
+	body := struct {
+		RangeStart   int    `json:"range_start"`
+		InsertBefore int    `json:"insert_before"`
+		RangeLength  int    `json:"range_length"`
+		SnapshotId   string `json:"snapshot_id,omitempty"`
+	}{rangeStart, insertBefore, rangeLength, snapshotId}
+
+	var response snapshotResponse
+	path := fmt.Sprintf("/playlists/%s/tracks", url.PathEscape(playlistId))
+	err := c.do(http.MethodPut, path, nil, body, &response)
+
+	return response.SnapshotId, err
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestPlaylists(t *testing.T) {
+	t.Run("it should create a playlist for the user", func(t *testing.T) {
+		// Given a round tripper checking the request
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodPost || req.URL.String() != "https://api.spotify.com/v1/users/user-id/playlists" {
+				t.Errorf("Unexpected request %s %s", req.Method, req.URL.String())
+			}
+			if req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("Expected a json body")
+			}
+
+			var details PlaylistDetails
+			body, _ := io.ReadAll(req.Body)
+			json.Unmarshal(body, &details)
+			if details.Name != "Name" || !details.Collaborative {
+				t.Errorf("Unexpected body %s", body)
+			}
+
+			return jsonResponse(http.StatusCreated, `{"id": "new-id", "snapshot_id": "s1"}`), nil
+		})
+
+		// When creating a playlist
+		playlist, err := client.CreatePlaylist("user-id", PlaylistDetails{Name: "Name", Collaborative: true})
+
+		// Then the created playlist should be returned
+		if err != nil || playlist.Id != "new-id" {
+			t.Errorf("Unexpected result %+v (%v)", playlist, err)
+		}
+	})
+
+	t.Run("it should add items and return the new snapshot", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			if req.URL.Path != "/v1/playlists/p1/tracks" || string(body) != `{"uris":["spotify:track:1","spotify:track:2"]}` {
+				t.Errorf("Unexpected request %s %s", req.URL.Path, body)
+			}
+
+			return jsonResponse(http.StatusCreated, `{"snapshot_id": "s2"}`), nil
+		})
+
+		snapshotId, err := client.AddItems("p1", []string{"spotify:track:1", "spotify:track:2"})
+
+		if err != nil || snapshotId != "s2" {
+			t.Errorf("Unexpected result %s (%v)", snapshotId, err)
+		}
+	})
+
+	t.Run("it should insert items at a position", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			if req.Method != http.MethodPost || string(body) != `{"uris":["spotify:track:1"],"position":0}` {
+				t.Errorf("Unexpected request %s %s", req.Method, body)
+			}
+
+			return jsonResponse(http.StatusCreated, `{"snapshot_id": "s2"}`), nil
+		})
+
+		snapshotId, err := client.InsertItems("p1", []string{"spotify:track:1"}, 0)
+
+		if err != nil || snapshotId != "s2" {
+			t.Errorf("Unexpected result %s (%v)", snapshotId, err)
+		}
+	})
+
+	t.Run("it should refuse more than 100 items", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			t.Errorf("No request expected")
+			return nil, nil
+		})
+
+		_, err := client.AddItems("p1", make([]string, 101))
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("it should remove items from the given snapshot", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			expected := `{"tracks":[{"uri":"spotify:track:1","positions":[3,7]}],"snapshot_id":"s1"}`
+			if req.Method != http.MethodDelete || string(body) != expected {
+				t.Errorf("Unexpected request %s %s", req.Method, body)
+			}
+
+			return jsonResponse(http.StatusOK, `{"snapshot_id": "s2"}`), nil
+		})
+
+		snapshotId, err := client.RemoveItems("p1", "s1", []ItemPositions{{Uri: "spotify:track:1", Positions: []int{3, 7}}})
+
+		if err != nil || snapshotId != "s2" {
+			t.Errorf("Unexpected result %s (%v)", snapshotId, err)
+		}
+	})
+
+	t.Run("it should move a range of items", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			expected := `{"range_start":5,"insert_before":0,"range_length":2,"snapshot_id":"s1"}`
+			if req.Method != http.MethodPut || string(body) != expected {
+				t.Errorf("Unexpected request %s %s", req.Method, body)
+			}
+
+			return jsonResponse(http.StatusOK, `{"snapshot_id": "s2"}`), nil
+		})
+
+		snapshotId, err := client.ReorderItems("p1", "s1", 5, 0, 2)
+
+		if err != nil || snapshotId != "s2" {
+			t.Errorf("Unexpected result %s (%v)", snapshotId, err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxSeeds is the number of artists, tracks and genres, together, which can
+// seed the recommendations and MaxRecommendations the number of tracks
+// recommended per request
+const (
+	MaxSeeds           = 5
+	MaxRecommendations = 100
+)
+
+// TunableAttributes are the attributes of the tracks which the
+// recommendations can be tuned on
+var TunableAttributes = []string{
+	"acousticness", "danceability", "duration_ms", "energy", "instrumentalness", "key", "liveness",
+	"loudness", "mode", "popularity", "speechiness", "tempo", "time_signature", "valence",
+}
+
+// AttributeRange bounds an attribute of the recommended tracks, Target
+// favors the tracks closest to the value
+type AttributeRange struct {
+	Min    *float64
+	Max    *float64
+	Target *float64
+}
+
+type RecommendationRequest struct {
+	SeedArtists []string
+	SeedTracks  []string
+	SeedGenres  []string
+	// Attributes are the ranges by tunable attribute
+	Attributes map[string]AttributeRange
+	// Limit is the number of tracks, up to MaxRecommendations
+	Limit  int
+	Market string
+}
+
+// Recommendations returns tracks similar to the seeds within the ranges of
+// the attributes
+func (c *Client) Recommendations(request RecommendationRequest) ([]Track, error) {
+	seeds := len(request.SeedArtists) + len(request.SeedTracks) + len(request.SeedGenres)
+	if seeds == 0 || seeds > MaxSeeds {
+		return nil, fmt.Errorf("expected between 1 and %d seeds, got %d", MaxSeeds, seeds)
+	}
+	if request.Limit > MaxRecommendations {
+		return nil, fmt.Errorf("cannot recommend more than %d tracks at once", MaxRecommendations)
+	}
+
+	params := url.Values{}
+	seedParams := map[string][]string{
+		"seed_artists": request.SeedArtists,
+		"seed_tracks":  request.SeedTracks,
+		"seed_genres":  request.SeedGenres,
+	}
+	for name, values := range seedParams {
+		if len(values) > 0 {
+			params.Set(name, strings.Join(values, ","))
+		}
+	}
+	for name, bounds := range request.Attributes {
+		if !slices.Contains(TunableAttributes, name) {
+			return nil, fmt.Errorf("unknown attribute %s", name)
+		}
+
+		for prefix, value := range map[string]*float64{"min_": bounds.Min, "max_": bounds.Max, "target_": bounds.Target} {
+			if value != nil {
+				params.Set(prefix+name, strconv.FormatFloat(*value, 'f', -1, 64))
+			}
+		}
+	}
+	if request.Limit > 0 {
+		params.Set("limit", strconv.Itoa(request.Limit))
+	}
+	if request.Market != "" {
+		params.Set("market", request.Market)
+	}
+
+	var response struct {
+		Tracks []Track `json:"tracks"`
+	}
+	if err := c.get("/recommendations", params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Tracks, nil
+}
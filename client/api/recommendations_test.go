@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRecommendations(t *testing.T) {
+	t.Run("it should request the recommendations of the seeds within the ranges", func(t *testing.T) {
+		// Given a recommendations response
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/recommendations?limit=10&max_energy=0.8&min_tempo=120&seed_artists=a1&seed_genres=house%2Cdisco&target_tempo=125": `{
+				"tracks": [{"id": "t1"}, {"id": "t2"}]
+			}`,
+		})
+		minTempo, targetTempo, maxEnergy := 120.0, 125.0, 0.8
+
+		// When requesting recommendations
+		tracks, err := client.Recommendations(RecommendationRequest{
+			SeedArtists: []string{"a1"},
+			SeedGenres:  []string{"house", "disco"},
+			Attributes: map[string]AttributeRange{
+				"tempo":  {Min: &minTempo, Target: &targetTempo},
+				"energy": {Max: &maxEnergy},
+			},
+			Limit: 10,
+		})
+
+		// Then the recommended tracks should be returned
+		if err != nil || len(tracks) != 2 || tracks[1].Id != "t2" {
+			t.Errorf("Unexpected tracks %+v (%v)", tracks, err)
+		}
+	})
+
+	t.Run("it should refuse too many seeds", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			t.Errorf("No request expected")
+			return nil, nil
+		})
+
+		_, err := client.Recommendations(RecommendationRequest{SeedGenres: []string{"a", "b", "c", "d", "e", "f"}})
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("it should refuse unknown attributes", func(t *testing.T) {
+		client := createClient(func(req *http.Request) (*http.Response, error) {
+			t.Errorf("No request expected")
+			return nil, nil
+		})
+		value := 1.0
+
+		_, err := client.Recommendations(RecommendationRequest{
+			SeedGenres: []string{"house"},
+			Attributes: map[string]AttributeRange{"bpm": {Min: &value}},
+		})
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
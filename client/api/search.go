@@ -0,0 +1,158 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Types of the search results
+const (
+	SearchTrack    = "track"
+	SearchAlbum    = "album"
+	SearchArtist   = "artist"
+	SearchPlaylist = "playlist"
+	SearchShow     = "show"
+	SearchEpisode  = "episode"
+)
+
+// MaxSearchLimit is the number of results of a type per search page, and
+// MaxSearchOffset the offset above which no result is returned
+const (
+	MaxSearchLimit  = 50
+	MaxSearchOffset = 1000
+)
+
+// Query holds the keywords and the field filters of a search
+type Query struct {
+	Keywords string
+	Track    string
+	Artist   string
+	Album    string
+	// FromYear alone filters a single year, with ToYear a range of years
+	FromYear int
+	ToYear   int
+	Isrc     string
+	// Genre filters the artists and the tracks
+	Genre string
+	// New filters the albums released in the past two weeks
+	New bool
+}
+
+// String returns the query in the syntax of the Web API, such as
+// `love artist:"Daft Punk" year:2000-2010`
+func (q Query) String() string {
+	var parts []string
+	if q.Keywords != "" {
+		parts = append(parts, q.Keywords)
+	}
+
+	filters := []struct{ name, value string }{
+		{"track", q.Track},
+		{"artist", q.Artist},
+		{"album", q.Album},
+		{"isrc", q.Isrc},
+		{"genre", q.Genre},
+	}
+	for _, filter := range filters {
+		if filter.value == "" {
+			continue
+		}
+
+		value := filter.value
+		if strings.ContainsAny(value, " \t\"") {
+			value = QuoteSearch(value)
+		}
+		parts = append(parts, filter.name+":"+value)
+	}
+
+	switch {
+	case q.FromYear != 0 && q.ToYear != 0 && q.ToYear != q.FromYear:
+		parts = append(parts, fmt.Sprintf("year:%d-%d", q.FromYear, q.ToYear))
+	case q.FromYear != 0:
+		parts = append(parts, fmt.Sprintf("year:%d", q.FromYear))
+	}
+	if q.New {
+		parts = append(parts, "tag:new")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// QuoteSearch wraps the value of a filter in double quotes. The search
+// syntax has no escaping, so the quotes within the value become spaces.
+func QuoteSearch(value string) string {
+	return `"` + strings.Join(strings.Fields(strings.ReplaceAll(value, `"`, " ")), " ") + `"`
+}
+
+type SearchOptions struct {
+	// Types of the results, tracks by default
+	Types []string
+	// Market filters the content playable in the market, such as MarketFromToken
+	Market string
+	// Limit is the number of results per type, up to MaxSearchLimit
+	Limit  int
+	Offset int
+}
+
+// SearchResults holds a page per requested type. Playlists, shows and
+// episodes may hold nil items for content which is not available.
+type SearchResults struct {
+	Tracks    Page[Track]               `json:"tracks"`
+	Albums    Page[SimplifiedAlbum]     `json:"albums"`
+	Artists   Page[Artist]              `json:"artists"`
+	Playlists Page[*SimplifiedPlaylist] `json:"playlists"`
+	Shows     Page[*SimplifiedShow]     `json:"shows"`
+	Episodes  Page[*Episode]            `json:"episodes"`
+}
+
+// Search returns a page of the results of every requested type
+func (c *Client) Search(query Query, options SearchOptions) (*SearchResults, error) {
+	types := options.Types
+	if len(types) == 0 {
+		types = []string{SearchTrack}
+	}
+
+	params := url.Values{"q": {query.String()}, "type": {strings.Join(types, ",")}}
+	if options.Market != "" {
+		params.Set("market", options.Market)
+	}
+	if options.Limit > 0 {
+		params.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Offset > 0 {
+		params.Set("offset", strconv.Itoa(options.Offset))
+	}
+
+	var results SearchResults
+	if err := c.get("/search", params, &results); err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+// SearchTracks returns the first tracks matching the query, which supports
+// the field filters of the Web API such as isrc: or artist:
+func (c *Client) SearchTracks(query string, limit int) ([]Track, error) {
+	return c.SearchTracksInMarket(query, "", limit)
+}
+
+// SearchTracksInMarket returns the first tracks matching the query with
+// their playability in the market, such as MarketFromToken
+func (c *Client) SearchTracksInMarket(query string, market string, limit int) ([]Track, error) {
+	var response struct {
+		Tracks Page[Track] `json:"tracks"`
+	}
+
+	params := url.Values{"q": {query}, "type": {"track"}, "limit": {strconv.Itoa(limit)}}
+	if market != "" {
+		params.Set("market", market)
+	}
+	if err := c.get("/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Tracks.Items, nil
+}
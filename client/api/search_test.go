@@ -0,0 +1,70 @@
+package api
+
+import "testing"
+
+func TestSearchTracks(t *testing.T) {
+	t.Run("it should search tracks with the given query", func(t *testing.T) {
+		// Given a search response
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/search?limit=5&q=isrc%3AUSRC17607839&type=track": `{"tracks": {"items": [{"id": "t1"}]}}`,
+		})
+
+		// When searching by isrc
+		tracks, err := client.SearchTracks("isrc:USRC17607839", 5)
+
+		// Then the tracks should be returned
+		if err != nil || len(tracks) != 1 || tracks[0].Id != "t1" {
+			t.Errorf("Unexpected tracks %+v (%v)", tracks, err)
+		}
+	})
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		query    Query
+		expected string
+	}{
+		{Query{Keywords: "love"}, "love"},
+		{Query{Keywords: "love", Artist: "Daft Punk", Album: "Discovery"}, `love artist:"Daft Punk" album:Discovery`},
+		{Query{Track: "One", FromYear: 1990, ToYear: 1999}, "track:One year:1990-1999"},
+		{Query{Isrc: "USRC17607839", FromYear: 2001, ToYear: 2001}, "isrc:USRC17607839 year:2001"},
+		{Query{Genre: "post rock", New: true}, `genre:"post rock" tag:new`},
+		{Query{Track: `Say "Hello"`, Album: `12"`}, `track:"Say Hello" album:"12"`},
+	}
+
+	for _, test := range tests {
+		t.Run("it should build "+test.expected, func(t *testing.T) {
+			if query := test.query.String(); query != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, query)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("it should search several types in a market", func(t *testing.T) {
+		// Given a search response with a page per type, holding an unavailable playlist
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/search?limit=2&market=from_token&offset=4&q=artist%3AAir&type=track%2Cplaylist": `{
+				"tracks": {"items": [{"id": "t1"}], "next": "https://api.spotify.com/v1/search?offset=6", "total": 10},
+				"playlists": {"items": [null, {"id": "p1"}], "total": 2}
+			}`,
+		})
+
+		// When searching the second page of tracks and playlists
+		results, err := client.Search(Query{Artist: "Air"}, SearchOptions{
+			Types:  []string{SearchTrack, SearchPlaylist},
+			Market: MarketFromToken,
+			Limit:  2,
+			Offset: 4,
+		})
+
+		// Then both pages should be returned
+		if err != nil || len(results.Tracks.Items) != 1 || results.Tracks.Next == "" || results.Tracks.Total != 10 {
+			t.Fatalf("Unexpected results %+v (%v)", results, err)
+		}
+		if len(results.Playlists.Items) != 2 || results.Playlists.Items[0] != nil || results.Playlists.Items[1].Id != "p1" {
+			t.Errorf("Unexpected playlists %+v", results.Playlists)
+		}
+	})
+}
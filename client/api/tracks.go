@@ -0,0 +1,38 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type AudioFeatures struct {
+	Id               string  `json:"id"`
+	Danceability     float64 `json:"danceability"`
+	Energy           float64 `json:"energy"`
+	Key              int     `json:"key"`
+	Mode             int     `json:"mode"`
+	Tempo            float64 `json:"tempo"`
+	Valence          float64 `json:"valence"`
+	Loudness         float64 `json:"loudness"`
+	TimeSignature    int     `json:"time_signature"`
+	Acousticness     float64 `json:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness"`
+	Speechiness      float64 `json:"speechiness"`
+	Liveness         float64 `json:"liveness"`
+}
+
+// AudioFeatures returns the features of up to MaxItemsPerRequest tracks, in
+// the order of the ids. Features are nil for unknown tracks.
+func (c *Client) AudioFeatures(ids []string) ([]*AudioFeatures, error) {
+	if len(ids) > MaxItemsPerRequest {
+		return nil, fmt.Errorf("cannot get the features of more than %d tracks at once", MaxItemsPerRequest)
+	}
+
+	var response struct {
+		AudioFeatures []*AudioFeatures `json:"audio_features"`
+	}
+	err := c.get("/audio-features", url.Values{"ids": {strings.Join(ids, ",")}}, &response)
+
+	return response.AudioFeatures, err
+}
@@ -0,0 +1,31 @@
+package api
+
+import "testing"
+
+func TestAudioFeatures(t *testing.T) {
+	t.Run("it should return the features in the order of the ids", func(t *testing.T) {
+		// Given a response with an unknown track
+		client := createRoutedClient(t, map[string]string{
+			"https://api.spotify.com/v1/audio-features?ids=t1%2Cunknown": `{"audio_features": [{"id": "t1", "tempo": 120.5, "key": 5}, null]}`,
+		})
+
+		// When getting the features
+		features, err := client.AudioFeatures([]string{"t1", "unknown"})
+
+		// Then both should be returned
+		if err != nil || len(features) != 2 {
+			t.Fatalf("Unexpected features %+v (%v)", features, err)
+		}
+		if features[0].Tempo != 120.5 || features[0].Key != 5 || features[1] != nil {
+			t.Errorf("Unexpected features %+v", features)
+		}
+	})
+
+	t.Run("it should refuse more than 100 ids", func(t *testing.T) {
+		client := createRoutedClient(t, map[string]string{})
+
+		if _, err := client.AudioFeatures(make([]string, 101)); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
@@ -4,11 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"prisco.dev/spotify-playlist/client/auth/callback"
 )
 
+// DefaultScopes are the scopes needed to read the library and to write playlists
+var DefaultScopes = []string{
+	"user-read-private",
+	"user-library-read",
+	"user-follow-read",
+	"playlist-read-private",
+	"playlist-read-collaborative",
+	"playlist-modify-public",
+	"playlist-modify-private",
+}
+
 type CommandExecutor interface {
 	executeCommand(string) error
 }
@@ -16,6 +28,7 @@ type CommandExecutor interface {
 type Authenticator struct {
 	clientId        string
 	redirectUrl     string
+	scopes          []string
 	commandExecutor CommandExecutor
 	pkceGenerator   PkceGenerator
 	callbackHandler callback.CallbackHandler
@@ -25,6 +38,7 @@ type Authenticator struct {
 func NewAuthenticator(
 	clientId string,
 	redirectUrl string,
+	scopes []string,
 	commandExecutor CommandExecutor,
 	pkceGenerator PkceGenerator,
 	callbackHandler callback.CallbackHandler,
@@ -33,6 +47,7 @@ func NewAuthenticator(
 	return &Authenticator{
 		clientId,
 		redirectUrl,
+		scopes,
 		commandExecutor,
 		pkceGenerator,
 		callbackHandler,
@@ -42,7 +57,7 @@ func NewAuthenticator(
 
 // Authenticate() starts the OAuth2 authentication flow using PKCE method
 func (a *Authenticator) Authenticate() error {
-	request, err := a.buildRequest()
+	request, verifier, err := a.buildRequest()
 
 	if err != nil {
 		return err
@@ -65,12 +80,14 @@ func (a *Authenticator) Authenticate() error {
 		return errors.New(callback.Err)
 	}
 
+	// The verifier is needed to exchange the code for a token
 	a.credentialStore.Code = callback.Code
+	a.credentialStore.Verifier = verifier
 
 	return nil
 }
 
-func (a *Authenticator) buildRequest() (*http.Request, error) {
+func (a *Authenticator) buildRequest() (*http.Request, string, error) {
 	request, err := http.NewRequest(
 		http.MethodGet,
 		"https://accounts.spotify.com/authorize",
@@ -78,7 +95,7 @@ func (a *Authenticator) buildRequest() (*http.Request, error) {
 	)
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, "", errors.New(fmt.Sprintf(
 			"Error in creating the http request %s",
 			err.Error(),
 		))
@@ -88,14 +105,14 @@ func (a *Authenticator) buildRequest() (*http.Request, error) {
 	q.Add("client_id", a.clientId)
 	q.Add("redirect_uri", a.redirectUrl)
 	q.Add("response_type", "code")
-	q.Add("scope", "user-read-private")
+	q.Add("scope", strings.Join(a.scopes, " "))
 	q.Add("code_challenge_method", "S256")
 
 	// Generate a code verifier using the provided generator
 	verifier, err := a.pkceGenerator.GenerateCodeVerifier()
 
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf(
+		return nil, "", errors.New(fmt.Sprintf(
 			"Error generating the code verifier: %s",
 			err.Error(),
 		))
@@ -105,5 +122,5 @@ func (a *Authenticator) buildRequest() (*http.Request, error) {
 
 	request.URL.RawQuery = q.Encode()
 
-	return request, nil
+	return request, verifier, nil
 }
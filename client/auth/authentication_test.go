@@ -77,7 +77,7 @@ func TestAuthenticator(t *testing.T) {
 					"code_challenge_method=S256&" +
 					"redirect_uri=redirectUrl&" +
 					"response_type=code&" +
-					"scope=user-read-private",
+					"scope=user-read-private+playlist-modify-private",
 				nil,
 			}
 
@@ -88,6 +88,7 @@ func TestAuthenticator(t *testing.T) {
 			authenticator := NewAuthenticator(
 				"clientId",
 				"redirectUrl",
+				[]string{"user-read-private", "playlist-modify-private"},
 				successfulCommandExecutor,
 				pkceGenerator,
 				MockSucceedingCallbackHandler,
@@ -106,6 +107,11 @@ func TestAuthenticator(t *testing.T) {
 			if credentialStore.Code != "mock code" {
 				t.Errorf("The code was not stored correctly: expected '%s', found '%s'", "mock code", credentialStore.Code)
 			}
+
+			// And the verifier should have been stored
+			if credentialStore.Verifier != "verifier" {
+				t.Errorf("The verifier was not stored correctly: expected '%s', found '%s'", "verifier", credentialStore.Verifier)
+			}
 		},
 	)
 
@@ -128,6 +134,7 @@ func TestAuthenticator(t *testing.T) {
 			authenticator := NewAuthenticator(
 				"clientId",
 				"redirectUrl",
+				[]string{"user-read-private", "playlist-modify-private"},
 				successfulCommandExecutor,
 				pkceGenerator,
 				MockSucceedingCallbackHandler,
@@ -162,6 +169,7 @@ func TestAuthenticator(t *testing.T) {
 			authenticator := NewAuthenticator(
 				"clientId",
 				"redirectUrl",
+				[]string{"user-read-private", "playlist-modify-private"},
 				successfulCommandExecutor,
 				pkceGenerator,
 				MockSucceedingCallbackHandler,
@@ -191,7 +199,7 @@ func TestAuthenticator(t *testing.T) {
 					"code_challenge_method=S256&" +
 					"redirect_uri=redirectUrl&" +
 					"response_type=code&" +
-					"scope=user-read-private",
+					"scope=user-read-private+playlist-modify-private",
 				nil,
 			}
 
@@ -199,6 +207,7 @@ func TestAuthenticator(t *testing.T) {
 			authenticator := NewAuthenticator(
 				"clientId",
 				"redirectUrl",
+				[]string{"user-read-private", "playlist-modify-private"},
 				successfulCommandExecutor,
 				pkceGenerator,
 				MockFailingCallbackHandler,
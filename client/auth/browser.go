@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// BrowserCommandExecutor runs the commands of the authenticator, replacing
+// the macOS `open` command with its equivalent on the other systems
+type BrowserCommandExecutor struct{}
+
+func (b BrowserCommandExecutor) executeCommand(command string) error {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if fields[0] == "open" {
+		switch runtime.GOOS {
+		case "linux", "freebsd", "openbsd", "netbsd":
+			fields[0] = "xdg-open"
+		case "windows":
+			fields = append([]string{"rundll32", "url.dll,FileProtocolHandler"}, fields[1:]...)
+		}
+	}
+
+	return exec.Command(fields[0], fields[1:]...).Start()
+}
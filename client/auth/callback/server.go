@@ -7,23 +7,30 @@ import (
 )
 
 func HandleCallback(timeout time.Duration) *CallbackResult {
-	channel := make(chan *CallbackResult)
-	handler := &CallbackContext{channel: channel}
+	return HandleCallbackOn(":8080")(timeout)
+}
 
-	// Spin up a server
-	server := http.Server{
-		Addr:    ":8080",
-		Handler: handler,
-	}
-	go server.ListenAndServe()
+// HandleCallbackOn returns a handler serving the callback on the given address
+func HandleCallbackOn(address string) CallbackHandler {
+	return func(timeout time.Duration) *CallbackResult {
+		channel := make(chan *CallbackResult)
+		handler := &CallbackContext{channel: channel}
 
-	// Catch the result
-	result := <-channel
+		// Spin up a server
+		server := http.Server{
+			Addr:    address,
+			Handler: handler,
+		}
+		go server.ListenAndServe()
 
-	// Shutdown the server right after receiving the callback result
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	server.Shutdown(ctx)
+		// Catch the result
+		result := <-channel
 
-	return result
+		// Shutdown the server right after receiving the callback result
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		server.Shutdown(ctx)
+
+		return result
+	}
 }
@@ -1,6 +1,60 @@
 package auth
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
 type Store struct {
-	Code  string
-	Token string
+	Code     string `json:"-"`
+	Verifier string `json:"-"`
+	// ClientId is the application the tokens were granted to, needed to
+	// refresh them
+	ClientId string `json:"client_id,omitempty"`
+	// UserId is the account the tokens were granted by
+	UserId       string    `json:"user_id,omitempty"`
+	Token        string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Scopes       []string  `json:"scopes,omitempty"`
+}
+
+// Expired reports whether the token expired, tokens without expiry never do
+func (s *Store) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// LoadStore reads the credentials saved at path
+func LoadStore(path string) (*Store, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var store Store
+	if err := json.Unmarshal(content, &store); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+
+	return &store, nil
+}
+
+// Save writes the credentials at path, readable by the current user only
+func (s *Store) Save(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	return nil
 }
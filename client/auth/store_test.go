@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("it should save and load the tokens only", func(t *testing.T) {
+		// Given a store after the authentication
+		path := filepath.Join(t.TempDir(), "credentials", "default.json")
+		store := &Store{Code: "code", Verifier: "verifier", Token: "token", RefreshToken: "refresh"}
+
+		// When saving and loading it
+		if err := store.Save(path); err != nil {
+			t.Fatalf("Save returned an error: %s", err.Error())
+		}
+		loaded, err := LoadStore(path)
+
+		// Then the tokens should be loaded
+		if err != nil || loaded.Token != "token" || loaded.RefreshToken != "refresh" {
+			t.Errorf("Unexpected store %+v (%v)", loaded, err)
+		}
+
+		// and the short lived secrets should not be saved
+		if loaded.Code != "" || loaded.Verifier != "" {
+			t.Errorf("Expected the code and verifier not to be saved, got %+v", loaded)
+		}
+
+		// and the file should be private
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+			t.Errorf("Expected private permissions, got %s", info.Mode().Perm())
+		}
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 type SpotifyTokenClient struct {
@@ -14,6 +16,22 @@ type SpotifyTokenClient struct {
 	redirectUri string
 }
 
+func NewSpotifyTokenClient(client *http.Client, clientId string, redirectUri string) *SpotifyTokenClient {
+	return &SpotifyTokenClient{client, clientId, redirectUri}
+}
+
+// Token is the response of the token endpoint
+type Token struct {
+	AccessToken string
+	// RefreshToken gets a new access token once it expired, it is empty when
+	// the refreshed token keeps the previous one
+	RefreshToken string
+	// ExpiresIn is the lifetime of the access token
+	ExpiresIn time.Duration
+	// Scopes are the scopes granted by the user
+	Scopes []string
+}
+
 type TokenClient interface {
 	GetToken(
 		code string,
@@ -25,8 +43,20 @@ func (s *SpotifyTokenClient) GetToken(
 	code string,
 	codeVerifier string,
 ) (string, error) {
-	const endpoint = "https://accounts.spotify.com/api/token"
+	token, err := s.Exchange(code, codeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
 
+// Exchange exchanges the authorization code for a token, along with its
+// lifetime and granted scopes
+func (s *SpotifyTokenClient) Exchange(
+	code string,
+	codeVerifier string,
+) (*Token, error) {
 	reqBody := url.Values{}
 	reqBody.Add("grant_type", "authorization_code")
 	reqBody.Add("code", code)
@@ -34,36 +64,63 @@ func (s *SpotifyTokenClient) GetToken(
 	reqBody.Add("client_id", s.clientId)
 	reqBody.Add("code_verifier", codeVerifier)
 
+	return s.requestToken(reqBody)
+}
+
+// Refresh gets a new access token with the refresh token of an expired one
+func (s *SpotifyTokenClient) Refresh(refreshToken string) (*Token, error) {
+	reqBody := url.Values{}
+	reqBody.Add("grant_type", "refresh_token")
+	reqBody.Add("refresh_token", refreshToken)
+	reqBody.Add("client_id", s.clientId)
+
+	return s.requestToken(reqBody)
+}
+
+func (s *SpotifyTokenClient) requestToken(reqBody url.Values) (*Token, error) {
+	const endpoint = "https://accounts.spotify.com/api/token"
+
 	resp, err := s.client.PostForm(endpoint, reqBody)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Check if the response status is OK
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("received non-OK response: %d", resp.StatusCode)
+		return nil, fmt.Errorf("received non-OK response: %d", resp.StatusCode)
 	}
 
 	// Read the response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Parse the JSON response
 	var responseMap map[string]interface{}
 	err = json.Unmarshal(body, &responseMap)
 	if err != nil {
-		return "", fmt.Errorf("failed to unmarshal JSON: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
 
 	// Extract the access_token field
 	accessToken, ok := responseMap["access_token"].(string)
 	if !ok {
-		return "", fmt.Errorf("access_token not found or is not a string")
+		return nil, fmt.Errorf("access_token not found or is not a string")
+	}
+
+	token := &Token{AccessToken: accessToken}
+	if refreshToken, ok := responseMap["refresh_token"].(string); ok {
+		token.RefreshToken = refreshToken
+	}
+	if expiresIn, ok := responseMap["expires_in"].(float64); ok {
+		token.ExpiresIn = time.Duration(expiresIn) * time.Second
+	}
+	if scope, ok := responseMap["scope"].(string); ok {
+		token.Scopes = strings.Fields(scope)
 	}
 
-	return accessToken, nil
+	return token, nil
 }
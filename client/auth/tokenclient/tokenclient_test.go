@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 )
 
 // To mock the http client, we mock the underlying roundtripper
@@ -188,6 +189,57 @@ func TestSpotifyTokenClient(t *testing.T) {
 	)
 }
 
+func TestExchange(t *testing.T) {
+	t.Run("it should return the lifetime and scopes of the token", func(t *testing.T) {
+		// Given a token endpoint granting two scopes
+		mockRoundTripper := &mockRoundTripper{
+			roundTripFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"access_token": "token", "expires_in": 3600, "scope": "user-read-private playlist-read-private"}`)),
+				}, nil
+			},
+		}
+		tokenClient := NewSpotifyTokenClient(&http.Client{Transport: mockRoundTripper}, "client-id", "redirect-uri")
+
+		// When exchanging the code
+		token, err := tokenClient.Exchange("a code", "a verifier")
+
+		// Then the lifetime and the scopes should be returned
+		if err != nil || token.ExpiresIn != time.Hour || len(token.Scopes) != 2 || token.Scopes[1] != "playlist-read-private" {
+			t.Errorf("Unexpected token %+v (%v)", token, err)
+		}
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	t.Run("it should request a new token with the refresh token", func(t *testing.T) {
+		// Given a token endpoint checking the refresh grant
+		mockRoundTripper := &mockRoundTripper{
+			roundTripFunc: func(req *http.Request) (*http.Response, error) {
+				req.ParseForm()
+				assertPostFormParam(t, req.PostForm, "grant_type", "refresh_token")
+				assertPostFormParam(t, req.PostForm, "refresh_token", "a refresh token")
+				assertPostFormParam(t, req.PostForm, "client_id", "client-id")
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"access_token": "token", "refresh_token": "new refresh token", "expires_in": 3600}`)),
+				}, nil
+			},
+		}
+		tokenClient := NewSpotifyTokenClient(&http.Client{Transport: mockRoundTripper}, "client-id", "redirect-uri")
+
+		// When refreshing the token
+		token, err := tokenClient.Refresh("a refresh token")
+
+		// Then the new tokens should be returned
+		if err != nil || token.AccessToken != "token" || token.RefreshToken != "new refresh token" || token.ExpiresIn != time.Hour {
+			t.Errorf("Unexpected token %+v (%v)", token, err)
+		}
+	})
+}
+
 func assertPostFormParam(t *testing.T, body url.Values, key string, expected string) {
 	if body.Get(key) != expected {
 		t.Errorf("Expected form to contain '%s': '%s', but got '%s'", key, expected, body.Get(key))
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/auth"
+)
+
+// EnvPrefix prefixes the environment variables overriding the keys, e.g.
+// SPOTIFY_PLAYLIST_CACHE_TTL for cache.ttl
+const EnvPrefix = "SPOTIFY_PLAYLIST_"
+
+// Config holds the settings of the command line, read from a json file and
+// overridden by the environment then by the flags of the commands
+type Config struct {
+	ClientId     string   `json:"client_id,omitempty"`
+	RedirectUrl  string   `json:"redirect_url,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	CallbackPort int      `json:"callback_port,omitempty"`
+	Concurrency  int      `json:"concurrency,omitempty"`
+	Export       Export   `json:"export"`
+	Cache        Cache    `json:"cache"`
+}
+
+type Export struct {
+	Format    string `json:"format,omitempty"`
+	Directory string `json:"dir,omitempty"`
+}
+
+type Cache struct {
+	Enabled   bool     `json:"enabled"`
+	Ttl       Duration `json:"ttl,omitempty"`
+	MaxSizeMb int      `json:"max_size_mb,omitempty"`
+}
+
+// Duration is a time.Duration stored as a string such as "1h30m"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+func Default() *Config {
+	return &Config{
+		Scopes:       auth.DefaultScopes,
+		CallbackPort: 8080,
+		Concurrency:  4,
+		Export:       Export{Format: "json", Directory: "."},
+		Cache:        Cache{Enabled: true, Ttl: Duration(time.Hour), MaxSizeMb: 100},
+	}
+}
+
+// Redirect returns the redirect url, by default the callback server on localhost
+func (c *Config) Redirect() string {
+	if c.RedirectUrl != "" {
+		return c.RedirectUrl
+	}
+
+	return fmt.Sprintf("http://localhost:%d/callback", c.CallbackPort)
+}
+
+// ListenPort returns the port of the local callback server, which is the
+// port of the redirect url so that the browser reaches the server
+func (c *Config) ListenPort() (int, error) {
+	if c.RedirectUrl == "" {
+		return c.CallbackPort, nil
+	}
+
+	redirect, err := url.Parse(c.RedirectUrl)
+	if err != nil || redirect.Host == "" {
+		return 0, fmt.Errorf("invalid redirect_url %q", c.RedirectUrl)
+	}
+
+	switch {
+	case redirect.Port() != "":
+		return strconv.Atoi(redirect.Port())
+	case redirect.Scheme == "https":
+		return 443, nil
+	default:
+		return 80, nil
+	}
+}
+
+// key describes a setting which can be read and written as a string
+type key struct {
+	get func(*Config) string
+	set func(*Config, string) error
+}
+
+var keys = map[string]key{
+	"client_id":    stringKey(func(c *Config) *string { return &c.ClientId }),
+	"redirect_url": stringKey(func(c *Config) *string { return &c.RedirectUrl }),
+	"scopes": {
+		get: func(c *Config) string { return strings.Join(c.Scopes, ",") },
+		set: func(c *Config, value string) error {
+			c.Scopes = nil
+			for _, scope := range strings.Split(value, ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					c.Scopes = append(c.Scopes, scope)
+				}
+			}
+			return nil
+		},
+	},
+	"callback_port": intKey(func(c *Config) *int { return &c.CallbackPort }, 1, 65535),
+	"concurrency":   intKey(func(c *Config) *int { return &c.Concurrency }, 1, 64),
+	"export.format": stringKey(func(c *Config) *string { return &c.Export.Format }),
+	"export.dir":    stringKey(func(c *Config) *string { return &c.Export.Directory }),
+	"cache.enabled": {
+		get: func(c *Config) string { return strconv.FormatBool(c.Cache.Enabled) },
+		set: func(c *Config, value string) error {
+			enabled, err := strconv.ParseBool(value)
+			c.Cache.Enabled = enabled
+			return err
+		},
+	},
+	"cache.ttl": {
+		get: func(c *Config) string { return time.Duration(c.Cache.Ttl).String() },
+		set: func(c *Config, value string) error { return c.Cache.Ttl.UnmarshalText([]byte(value)) },
+	},
+	"cache.max_size_mb": intKey(func(c *Config) *int { return &c.Cache.MaxSizeMb }, 1, 1<<20),
+}
+
+func stringKey(field func(*Config) *string) key {
+	return key{
+		get: func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func intKey(field func(*Config) *int, min int, max int) key {
+	return key{
+		get: func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, value string) error {
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if number < min || number > max {
+				return fmt.Errorf("expected a value between %d and %d", min, max)
+			}
+
+			*field(c) = number
+			return nil
+		},
+	}
+}
+
+// Keys returns the names of the settings, sorted
+func Keys() []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// EnvName returns the environment variable overriding the key
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
+
+func (c *Config) Get(name string) (string, error) {
+	key, ok := keys[name]
+	if !ok {
+		return "", fmt.Errorf("unknown key %s, available keys: %s", name, strings.Join(Keys(), ", "))
+	}
+
+	return key.get(c), nil
+}
+
+func (c *Config) Set(name string, value string) error {
+	key, ok := keys[name]
+	if !ok {
+		return fmt.Errorf("unknown key %s, available keys: %s", name, strings.Join(Keys(), ", "))
+	}
+
+	if err := key.set(c, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// LoadFile reads the configuration file over the defaults, a missing file
+// leaves the defaults
+func LoadFile(path string) (*Config, error) {
+	config := Default()
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Load reads the configuration file and applies the environment overrides
+func Load(path string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	config, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range Keys() {
+		value, ok := lookupEnv(EnvName(name))
+		if !ok {
+			continue
+		}
+
+		if err := config.Set(name, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvName(name), err)
+		}
+	}
+
+	return config, nil
+}
+
+func (c *Config) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
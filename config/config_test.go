@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("it should use the defaults without a file", func(t *testing.T) {
+		config, err := Load(filepath.Join(t.TempDir(), "config.json"), noEnv)
+
+		if err != nil || config.Export.Format != "json" || !config.Cache.Enabled || config.Redirect() != "http://localhost:8080/callback" {
+			t.Errorf("Unexpected config %+v (%v)", config, err)
+		}
+	})
+
+	t.Run("it should override the defaults with the file then the environment", func(t *testing.T) {
+		// Given a file setting the client id and the ttl
+		path := filepath.Join(t.TempDir(), "config.json")
+		os.WriteFile(path, []byte(`{"client_id": "file", "cache": {"ttl": "2h"}}`), 0o600)
+
+		// and an environment overriding the client id
+		env := func(name string) (string, bool) {
+			if name == "SPOTIFY_PLAYLIST_CLIENT_ID" {
+				return "env", true
+			}
+			return "", false
+		}
+
+		// When loading the config
+		config, err := Load(path, env)
+
+		// Then the environment should win over the file
+		if err != nil || config.ClientId != "env" {
+			t.Fatalf("Unexpected client id %s (%v)", config.ClientId, err)
+		}
+
+		// and the file over the defaults, keeping the other defaults
+		if time.Duration(config.Cache.Ttl) != 2*time.Hour || !config.Cache.Enabled || config.Cache.MaxSizeMb != 100 {
+			t.Errorf("Unexpected cache %+v", config.Cache)
+		}
+	})
+
+	t.Run("it should return an error for invalid environment values", func(t *testing.T) {
+		env := func(name string) (string, bool) {
+			return "many", name == "SPOTIFY_PLAYLIST_CONCURRENCY"
+		}
+
+		if _, err := Load(filepath.Join(t.TempDir(), "config.json"), env); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		valid bool
+	}{
+		{"client_id", "abc", true},
+		{"scopes", "user-read-private, playlist-read-private", true},
+		{"callback_port", "9000", true},
+		{"callback_port", "70000", false},
+		{"cache.enabled", "false", true},
+		{"cache.enabled", "maybe", false},
+		{"cache.ttl", "30m", true},
+		{"cache.ttl", "soon", false},
+		{"unknown", "value", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key+"="+test.value, func(t *testing.T) {
+			config := Default()
+
+			err := config.Set(test.key, test.value)
+
+			if (err == nil) != test.valid {
+				t.Errorf("Expected valid to be %v, got %v", test.valid, err)
+			}
+		})
+	}
+
+	t.Run("it should read back the written values", func(t *testing.T) {
+		config := Default()
+		config.Set("scopes", "a, b")
+
+		if value, _ := config.Get("scopes"); value != "a,b" {
+			t.Errorf("Expected 'a,b', got '%s'", value)
+		}
+	})
+}
+
+func TestListenPort(t *testing.T) {
+	tests := []struct {
+		redirectUrl string
+		expected    int
+	}{
+		{"", 8080},
+		{"http://127.0.0.1:9000/callback", 9000},
+		{"http://localhost/callback", 80},
+		{"https://example.com/callback", 443},
+	}
+
+	for _, test := range tests {
+		t.Run("it should listen on "+strconv.Itoa(test.expected)+" for '"+test.redirectUrl+"'", func(t *testing.T) {
+			config := Default()
+			config.RedirectUrl = test.redirectUrl
+
+			if port, err := config.ListenPort(); err != nil || port != test.expected {
+				t.Errorf("Expected %d, got %d (%v)", test.expected, port, err)
+			}
+		})
+	}
+
+	t.Run("it should return an error for an invalid redirect url", func(t *testing.T) {
+		config := Default()
+		config.RedirectUrl = "localhost:9000"
+
+		if _, err := config.ListenPort(); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+func TestSave(t *testing.T) {
+	t.Run("it should save the values to the file", func(t *testing.T) {
+		// Given a config with a custom export directory
+		path := filepath.Join(t.TempDir(), "spotify-playlist", "config.json")
+		config := Default()
+		config.Set("export.dir", "/exports")
+		config.Set("cache.ttl", "15m")
+
+		// When saving and loading it
+		if err := config.Save(path); err != nil {
+			t.Fatalf("Save returned an error: %s", err.Error())
+		}
+		loaded, err := LoadFile(path)
+
+		// Then the values should be kept
+		if err != nil || loaded.Export.Directory != "/exports" || time.Duration(loaded.Cache.Ttl) != 15*time.Minute {
+			t.Errorf("Unexpected config %+v (%v)", loaded, err)
+		}
+	})
+}
+
+// Helpers
+func noEnv(string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,188 @@
+package dedupe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/matcher"
+)
+
+// DefaultMinScore is the matcher score above which two different tracks are
+// considered the same song
+const DefaultMinScore = 0.9
+
+type Occurrence struct {
+	Playlist export.Playlist `json:"playlist"`
+	Track    export.Track    `json:"track"`
+}
+
+// Duplicate is a repeated occurrence of the Original track, which comes
+// first in the given playlists
+type Duplicate struct {
+	Original  Occurrence `json:"original"`
+	Duplicate Occurrence `json:"duplicate"`
+	// Exact duplicates share the same uri
+	Exact   bool     `json:"exact"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type Options struct {
+	// Fuzzy also reports different tracks of the same song, such as the
+	// single and the album release
+	Fuzzy    bool
+	MinScore float64
+}
+
+// Find reports the duplicates within and across the playlists, the first
+// occurrence of a track in the order of the playlists is the original
+func Find(entries []export.PlaylistEntry, options Options) []Duplicate {
+	if options.MinScore == 0 {
+		options.MinScore = DefaultMinScore
+	}
+
+	trackMatcher := matcher.NewMatcher()
+	var duplicates []Duplicate
+
+	originalsByUri := map[string]Occurrence{}
+	originalsByIsrc := map[string]Occurrence{}
+	// Fuzzy candidates are only compared within the same normalized title
+	originalsByTitle := map[string][]Occurrence{}
+
+	for _, entry := range entries {
+		for _, track := range entry.Tracks {
+			occurrence := Occurrence{Playlist: entry.Playlist, Track: track}
+
+			if track.Uri != "" {
+				if original, ok := originalsByUri[track.Uri]; ok {
+					duplicates = append(duplicates, Duplicate{
+						Original:  original,
+						Duplicate: occurrence,
+						Exact:     true,
+						Score:     1,
+						Reasons:   []string{"same uri"},
+					})
+					continue
+				}
+				originalsByUri[track.Uri] = occurrence
+			}
+
+			if !options.Fuzzy {
+				continue
+			}
+
+			if track.Isrc != "" {
+				if original, ok := originalsByIsrc[track.Isrc]; ok {
+					duplicates = append(duplicates, Duplicate{
+						Original:  original,
+						Duplicate: occurrence,
+						Score:     1,
+						Reasons:   []string{"same ISRC"},
+					})
+					continue
+				}
+				originalsByIsrc[track.Isrc] = occurrence
+			}
+
+			title := matcher.NormalizeTitle(track.Name)
+			var best *Duplicate
+			for _, original := range originalsByTitle[title] {
+				result := trackMatcher.Score(toMatcherTrack(original.Track), toMatcherTrack(track))
+				if result.Score >= options.MinScore && (best == nil || result.Score > best.Score) {
+					best = &Duplicate{Original: original, Duplicate: occurrence, Score: result.Score, Reasons: result.Reasons}
+				}
+			}
+
+			if best != nil {
+				duplicates = append(duplicates, *best)
+				continue
+			}
+			originalsByTitle[title] = append(originalsByTitle[title], occurrence)
+		}
+	}
+
+	return duplicates
+}
+
+type Client interface {
+	RemoveItems(playlistId string, snapshotId string, items []api.ItemPositions) (string, error)
+}
+
+// Remove deletes the duplicate occurrences from their playlist, starting
+// from the last positions so that the removals do not shift the others
+func Remove(client Client, duplicates []Duplicate) error {
+	byPlaylist := map[string][]Occurrence{}
+	var playlistIds []string
+	for _, duplicate := range duplicates {
+		id := duplicate.Duplicate.Playlist.Id
+		if _, ok := byPlaylist[id]; !ok {
+			playlistIds = append(playlistIds, id)
+		}
+		byPlaylist[id] = append(byPlaylist[id], duplicate.Duplicate)
+	}
+
+	for _, playlistId := range playlistIds {
+		occurrences := byPlaylist[playlistId]
+		sort.Slice(occurrences, func(i, j int) bool {
+			return occurrences[i].Track.Position > occurrences[j].Track.Position
+		})
+
+		snapshotId := occurrences[0].Playlist.SnapshotId
+		for start := 0; start < len(occurrences); start += api.MaxItemsPerRequest {
+			end := min(start+api.MaxItemsPerRequest, len(occurrences))
+
+			var items []api.ItemPositions
+			for _, occurrence := range occurrences[start:end] {
+				items = append(items, api.ItemPositions{Uri: occurrence.Track.Uri, Positions: []int{occurrence.Track.Position}})
+			}
+
+			newSnapshotId, err := client.RemoveItems(playlistId, snapshotId, items)
+			if err != nil {
+				return fmt.Errorf("failed to remove duplicates from playlist %s: %w", playlistId, err)
+			}
+			snapshotId = newSnapshotId
+		}
+	}
+
+	return nil
+}
+
+// PrintReport writes a line per duplicate
+func PrintReport(writer io.Writer, duplicates []Duplicate) error {
+	var out strings.Builder
+	for _, duplicate := range duplicates {
+		kind := "exact"
+		if !duplicate.Exact {
+			kind = fmt.Sprintf("fuzzy %.0f%%: %s", duplicate.Score*100, strings.Join(duplicate.Reasons, ", "))
+		}
+
+		fmt.Fprintf(
+			&out, "%s #%d %s duplicates %s #%d %s (%s)\n",
+			duplicate.Duplicate.Playlist.Name, duplicate.Duplicate.Track.Position+1, describe(duplicate.Duplicate.Track),
+			duplicate.Original.Playlist.Name, duplicate.Original.Track.Position+1, describe(duplicate.Original.Track),
+			kind,
+		)
+	}
+	fmt.Fprintf(&out, "%d duplicates found\n", len(duplicates))
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func describe(track export.Track) string {
+	return strings.Join(track.Artists, ", ") + " - " + track.Name
+}
+
+func toMatcherTrack(track export.Track) matcher.Track {
+	return matcher.Track{
+		Id:         track.Id,
+		Title:      track.Name,
+		Artists:    track.Artists,
+		DurationMs: track.DurationMs,
+		Isrc:       track.Isrc,
+	}
+}
@@ -0,0 +1,143 @@
+package dedupe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Client recording the removals
+type mockClient struct {
+	calls []string
+
+	removeError error
+}
+
+func (m *mockClient) RemoveItems(playlistId string, snapshotId string, items []api.ItemPositions) (string, error) {
+	if m.removeError != nil {
+		return "", m.removeError
+	}
+
+	m.calls = append(m.calls, fmt.Sprintf("%s@%s:%d items from %d", playlistId, snapshotId, len(items), items[0].Positions[0]))
+	return snapshotId + "+", nil
+}
+
+func TestFind(t *testing.T) {
+	single := export.Track{Uri: "spotify:track:single", Name: "Song", Artists: []string{"Artist"}, Isrc: "ISRC1", DurationMs: 200000}
+	album := export.Track{Uri: "spotify:track:album", Name: "Song - Album Version", Artists: []string{"Artist"}, Isrc: "ISRC1", DurationMs: 201000}
+	remaster := export.Track{Uri: "spotify:track:remaster", Name: "Song - Remastered 2011", Artists: []string{"Artist"}, DurationMs: 200500}
+	other := export.Track{Uri: "spotify:track:other", Name: "Other Song", Artists: []string{"Artist"}}
+
+	entries := []export.PlaylistEntry{
+		entry("p1", single, other, single),
+		entry("p2", album, remaster, other),
+	}
+
+	t.Run("it should find the exact duplicates within and across playlists", func(t *testing.T) {
+		duplicates := Find(entries, Options{})
+
+		expected := []string{"p1#2 of p1#0", "p2#2 of p1#1"}
+		if summary := summarize(duplicates); !reflect.DeepEqual(summary, expected) {
+			t.Errorf("Expected %v, got %v", expected, summary)
+		}
+		if !duplicates[0].Exact {
+			t.Errorf("Expected an exact duplicate")
+		}
+	})
+
+	t.Run("it should find the fuzzy duplicates by ISRC and by matching", func(t *testing.T) {
+		duplicates := Find(entries, Options{Fuzzy: true})
+
+		expected := []string{"p1#2 of p1#0", "p2#0 of p1#0", "p2#1 of p1#0", "p2#2 of p1#1"}
+		if summary := summarize(duplicates); !reflect.DeepEqual(summary, expected) {
+			t.Fatalf("Expected %v, got %v", expected, summary)
+		}
+		if duplicates[1].Exact || duplicates[1].Reasons[0] != "same ISRC" {
+			t.Errorf("Expected an ISRC duplicate, got %+v", duplicates[1])
+		}
+		if duplicates[2].Score < DefaultMinScore {
+			t.Errorf("Expected a fuzzy duplicate above the threshold, got %+v", duplicates[2])
+		}
+	})
+
+	t.Run("it should print a line per duplicate", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		PrintReport(buffer, Find(entries[:1], Options{}))
+
+		expected := "p1 #3 Artist - Song duplicates p1 #1 Artist - Song (exact)\n1 duplicates found\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("it should remove from the last position with chained snapshots", func(t *testing.T) {
+		// Given 150 duplicates in p1 and one in p2
+		var duplicates []Duplicate
+		for i := 0; i < 150; i++ {
+			duplicates = append(duplicates, duplicate("p1", i))
+		}
+		duplicates = append(duplicates, duplicate("p2", 4))
+		client := &mockClient{}
+
+		// When removing them
+		err := Remove(client, duplicates)
+
+		// Then the removal should be batched per playlist starting from the end
+		if err != nil {
+			t.Fatalf("Remove returned an error: %s", err.Error())
+		}
+		expected := []string{
+			"p1@s1:100 items from 149",
+			"p1@s1+:50 items from 49",
+			"p2@s1:1 items from 4",
+		}
+		if !reflect.DeepEqual(client.calls, expected) {
+			t.Errorf("Expected %v, got %v", expected, client.calls)
+		}
+	})
+
+	t.Run("it should return the error of the client", func(t *testing.T) {
+		err := Remove(&mockClient{removeError: errors.New("snapshot outdated")}, []Duplicate{duplicate("p1", 0)})
+
+		if err == nil || err.Error() != "failed to remove duplicates from playlist p1: snapshot outdated" {
+			t.Errorf("Unexpected error %v", err)
+		}
+	})
+}
+
+// Helpers
+func entry(id string, tracks ...export.Track) export.PlaylistEntry {
+	entry := export.PlaylistEntry{Playlist: export.Playlist{Id: id, Name: id, SnapshotId: "s1"}}
+	for i, track := range tracks {
+		track.Position = i
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	return entry
+}
+
+func duplicate(playlistId string, position int) Duplicate {
+	return Duplicate{Duplicate: Occurrence{
+		Playlist: export.Playlist{Id: playlistId, SnapshotId: "s1"},
+		Track:    export.Track{Uri: "spotify:track:x", Position: position},
+	}}
+}
+
+func summarize(duplicates []Duplicate) []string {
+	var summary []string
+	for _, d := range duplicates {
+		summary = append(summary, fmt.Sprintf(
+			"%s#%d of %s#%d",
+			d.Duplicate.Playlist.Id, d.Duplicate.Track.Position, d.Original.Playlist.Id, d.Original.Track.Position,
+		))
+	}
+
+	return summary
+}
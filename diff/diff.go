@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/sequence"
+)
+
+// Change is a track which was added, removed or moved, positions are -1 when
+// the track is not part of the corresponding version of the playlist
+type Change struct {
+	Track export.Track `json:"track"`
+	From  int          `json:"from"`
+	To    int          `json:"to"`
+}
+
+type Result struct {
+	Old       export.Playlist `json:"old"`
+	New       export.Playlist `json:"new"`
+	Added     []Change        `json:"added"`
+	Removed   []Change        `json:"removed"`
+	Reordered []Change        `json:"reordered"`
+}
+
+func (r *Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Reordered) == 0
+}
+
+// Compare computes the changes between two versions of a playlist. Tracks
+// are matched by uri, repeated tracks are matched by occurrence. A track is
+// reordered when it is not part of the longest sequence of tracks kept in
+// the same relative order.
+func Compare(old export.PlaylistEntry, new export.PlaylistEntry) *Result {
+	result := &Result{
+		Old:       old.Playlist,
+		New:       new.Playlist,
+		Added:     []Change{},
+		Removed:   []Change{},
+		Reordered: []Change{},
+	}
+
+	oldKeys := keys(old.Tracks)
+	newKeys := keys(new.Tracks)
+
+	oldPositions := map[string]int{}
+	for i, key := range oldKeys {
+		oldPositions[key] = i
+	}
+
+	// Old positions of the kept tracks, in the new order
+	var kept []int
+	var keptNew []int
+	newPositions := map[string]bool{}
+	for i, key := range newKeys {
+		newPositions[key] = true
+
+		from, ok := oldPositions[key]
+		if !ok {
+			result.Added = append(result.Added, Change{Track: new.Tracks[i], From: -1, To: i})
+			continue
+		}
+
+		kept = append(kept, from)
+		keptNew = append(keptNew, i)
+	}
+
+	for i, key := range oldKeys {
+		if !newPositions[key] {
+			result.Removed = append(result.Removed, Change{Track: old.Tracks[i], From: i, To: -1})
+		}
+	}
+
+	inOrder := sequence.LongestIncreasing(kept)
+	for i, from := range kept {
+		if !inOrder[i] {
+			to := keptNew[i]
+			result.Reordered = append(result.Reordered, Change{Track: new.Tracks[to], From: from, To: to})
+		}
+	}
+
+	return result
+}
+
+func keys(tracks []export.Track) []string {
+	occurrences := map[string]int{}
+	result := make([]string, len(tracks))
+	for i, track := range tracks {
+		key := track.Uri
+		if key == "" {
+			key = track.Id
+		}
+		if key == "" {
+			key = track.Name + "\x00" + track.Album
+		}
+
+		result[i] = fmt.Sprintf("%s#%d", key, occurrences[key])
+		occurrences[key]++
+	}
+
+	return result
+}
@@ -0,0 +1,63 @@
+package diff
+
+import (
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestCompare(t *testing.T) {
+	t.Run("it should report added, removed and reordered tracks", func(t *testing.T) {
+		// Given two versions of a playlist
+		old := entry("a", "b", "c", "d", "e")
+		new := entry("b", "a", "c", "e", "f")
+
+		// When comparing them
+		result := Compare(old, new)
+
+		// Then f should be added
+		if len(result.Added) != 1 || result.Added[0].Track.Uri != "f" || result.Added[0].To != 4 {
+			t.Errorf("Unexpected added tracks %+v", result.Added)
+		}
+
+		// and d removed
+		if len(result.Removed) != 1 || result.Removed[0].Track.Uri != "d" || result.Removed[0].From != 3 {
+			t.Errorf("Unexpected removed tracks %+v", result.Removed)
+		}
+
+		// and a single track reported as moved
+		if len(result.Reordered) != 1 {
+			t.Fatalf("Expected a single reordered track, got %+v", result.Reordered)
+		}
+		moved := result.Reordered[0]
+		if moved.Track.Uri != "b" || moved.From != 1 || moved.To != 0 {
+			t.Errorf("Unexpected reordered track %+v", moved)
+		}
+	})
+
+	t.Run("it should match repeated tracks by occurrence", func(t *testing.T) {
+		result := Compare(entry("a", "b", "a"), entry("a", "b"))
+
+		if len(result.Removed) != 1 || result.Removed[0].From != 2 || len(result.Added) != 0 || len(result.Reordered) != 0 {
+			t.Errorf("Expected the second occurrence to be removed, got %+v", result)
+		}
+	})
+
+	t.Run("it should report no changes for identical playlists", func(t *testing.T) {
+		result := Compare(entry("a", "b", "c"), entry("a", "b", "c"))
+
+		if !result.Empty() {
+			t.Errorf("Expected no changes, got %+v", result)
+		}
+	})
+}
+
+// Helpers
+func entry(uris ...string) export.PlaylistEntry {
+	entry := export.PlaylistEntry{Playlist: export.Playlist{Id: "p1", Name: "Playlist"}}
+	for i, uri := range uris {
+		entry.Tracks = append(entry.Tracks, export.Track{Position: i, Uri: uri, Name: "Song " + uri})
+	}
+
+	return entry
+}
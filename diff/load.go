@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"prisco.dev/spotify-playlist/backup"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Load reads a version of the playlist from a json export, a backup archive
+// or an export directory. The playlist id can be omitted for exports holding
+// a single playlist.
+func Load(path string, playlistId string) (export.PlaylistEntry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return export.PlaylistEntry{}, err
+	}
+
+	if info.IsDir() {
+		file, err := findInDirectory(path, playlistId)
+		if err != nil {
+			return export.PlaylistEntry{}, err
+		}
+		path = file
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return export.PlaylistEntry{}, err
+	}
+	defer file.Close()
+
+	document, err := export.ReadDocument(file)
+	if err != nil {
+		return export.PlaylistEntry{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, entry := range document.Playlists {
+		if entry.Playlist.Id == playlistId || (playlistId == "" && len(document.Playlists) == 1) {
+			return entry, nil
+		}
+	}
+
+	return export.PlaylistEntry{}, fmt.Errorf("playlist %s not found in %s", playlistId, path)
+}
+
+// LoadLive fetches the current version of the playlist from the source
+func LoadLive(source export.PlaylistSource, playlistId string) (export.PlaylistEntry, error) {
+	playlists, err := source.Playlists()
+	if err != nil {
+		return export.PlaylistEntry{}, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	for _, playlist := range playlists {
+		if playlist.Id != playlistId {
+			continue
+		}
+
+		entry := export.PlaylistEntry{Playlist: playlist}
+		err := source.Tracks(playlist, func(track export.Track) error {
+			entry.Tracks = append(entry.Tracks, track)
+			return nil
+		})
+
+		return entry, err
+	}
+
+	return export.PlaylistEntry{}, fmt.Errorf("playlist %s not found in the library", playlistId)
+}
+
+func findInDirectory(directory string, playlistId string) (string, error) {
+	if playlistId == "" {
+		return "", fmt.Errorf("a playlist id is required to diff the directory %s", directory)
+	}
+
+	if manifest, err := backup.ReadManifest(directory); err == nil {
+		for _, file := range manifest.Files {
+			if file.PlaylistId == playlistId {
+				return filepath.Join(directory, filepath.FromSlash(file.Path)), nil
+			}
+		}
+
+		return "", fmt.Errorf("playlist %s not found in the archive %s", playlistId, directory)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(directory, "*_"+playlistId+".json"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("playlist %s not found in %s", playlistId, directory)
+	}
+
+	return matches[0], nil
+}
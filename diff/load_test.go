@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/snapshot"
+)
+
+// Mock Playlist Source
+type mockSource struct {
+	entries []export.PlaylistEntry
+}
+
+func (m mockSource) Playlists() ([]export.Playlist, error) {
+	var playlists []export.Playlist
+	for _, entry := range m.entries {
+		playlists = append(playlists, entry.Playlist)
+	}
+	return playlists, nil
+}
+
+func (m mockSource) Tracks(playlist export.Playlist, handle func(export.Track) error) error {
+	for _, entry := range m.entries {
+		if entry.Playlist.Id != playlist.Id {
+			continue
+		}
+		for _, track := range entry.Tracks {
+			if err := handle(track); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestLoad(t *testing.T) {
+	format, _ := export.ParseFormat("json")
+	source := mockSource{[]export.PlaylistEntry{entry("a", "b")}}
+
+	t.Run("it should load a json export", func(t *testing.T) {
+		// Given a json export
+		path := filepath.Join(t.TempDir(), "export.json")
+		file, _ := os.Create(path)
+		export.Export(source, format, file, export.Options{})
+		file.Close()
+
+		// When loading it without a playlist id
+		loaded, err := Load(path, "")
+
+		// Then the single playlist should be returned
+		if err != nil {
+			t.Fatalf("Load returned an error: %s", err.Error())
+		}
+		if loaded.Playlist.Id != "p1" || len(loaded.Tracks) != 2 {
+			t.Errorf("Unexpected playlist %+v", loaded)
+		}
+	})
+
+	t.Run("it should find the playlist in an export directory", func(t *testing.T) {
+		// Given a synced directory
+		directory := t.TempDir()
+		snapshot.Sync(source, format, directory, export.Options{})
+
+		// When loading the playlist from it
+		loaded, err := Load(directory, "p1")
+
+		// Then it should be found
+		if err != nil || len(loaded.Tracks) != 2 {
+			t.Errorf("Expected the playlist to be loaded, got %+v (%v)", loaded, err)
+		}
+	})
+
+	t.Run("it should return an error for unknown playlists", func(t *testing.T) {
+		_, err := Load(t.TempDir(), "unknown")
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+
+	t.Run("it should load the live version from the source", func(t *testing.T) {
+		loaded, err := LoadLive(source, "p1")
+
+		if err != nil || len(loaded.Tracks) != 2 || loaded.Tracks[1].Uri != "b" {
+			t.Errorf("Expected the live playlist, got %+v (%v)", loaded, err)
+		}
+	})
+}
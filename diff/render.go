@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+const (
+	OutputText     = "text"
+	OutputJson     = "json"
+	OutputMarkdown = "markdown"
+)
+
+func Render(writer io.Writer, result *Result, output string) error {
+	switch output {
+	case OutputText:
+		return renderText(writer, result)
+	case OutputJson:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case OutputMarkdown:
+		return renderMarkdown(writer, result)
+	}
+
+	return fmt.Errorf("unknown diff output: %s", output)
+}
+
+func renderText(writer io.Writer, result *Result) error {
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s: %s\n", result.New.Name, summary(result))
+
+	for _, change := range result.Added {
+		fmt.Fprintf(&out, "+ %d %s%s\n", change.To+1, describe(change.Track), addedBy(change.Track))
+	}
+	for _, change := range result.Removed {
+		fmt.Fprintf(&out, "- %d %s\n", change.From+1, describe(change.Track))
+	}
+	for _, change := range result.Reordered {
+		fmt.Fprintf(&out, "~ %d -> %d %s\n", change.From+1, change.To+1, describe(change.Track))
+	}
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func renderMarkdown(writer io.Writer, result *Result) error {
+	var out strings.Builder
+	fmt.Fprintf(&out, "# %s\n\n%s\n", escapeMarkdown(result.New.Name), summary(result))
+
+	if len(result.Added) > 0 {
+		out.WriteString("\n## Added\n\n| # | Track | Added by | Added at |\n|---|---|---|---|\n")
+		for _, change := range result.Added {
+			fmt.Fprintf(
+				&out, "| %d | %s | %s | %s |\n",
+				change.To+1, escapeMarkdown(describe(change.Track)), escapeMarkdown(change.Track.AddedBy), formatTime(change.Track.AddedAt),
+			)
+		}
+	}
+
+	if len(result.Removed) > 0 {
+		out.WriteString("\n## Removed\n\n| # | Track |\n|---|---|\n")
+		for _, change := range result.Removed {
+			fmt.Fprintf(&out, "| %d | %s |\n", change.From+1, escapeMarkdown(describe(change.Track)))
+		}
+	}
+
+	if len(result.Reordered) > 0 {
+		out.WriteString("\n## Reordered\n\n| From | To | Track |\n|---|---|---|\n")
+		for _, change := range result.Reordered {
+			fmt.Fprintf(&out, "| %d | %d | %s |\n", change.From+1, change.To+1, escapeMarkdown(describe(change.Track)))
+		}
+	}
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func summary(result *Result) string {
+	return fmt.Sprintf("%d added, %d removed, %d reordered", len(result.Added), len(result.Removed), len(result.Reordered))
+}
+
+func describe(track export.Track) string {
+	if len(track.Artists) == 0 {
+		return track.Name
+	}
+
+	return fmt.Sprintf("%s - %s", strings.Join(track.Artists, ", "), track.Name)
+}
+
+func addedBy(track export.Track) string {
+	if track.AddedBy == "" && track.AddedAt.IsZero() {
+		return ""
+	}
+
+	return fmt.Sprintf(" (added by %s on %s)", track.AddedBy, formatTime(track.AddedAt))
+}
+
+func formatTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.Format("2006-01-02 15:04")
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "#", `\#`)
+
+func escapeMarkdown(value string) string {
+	return markdownEscaper.Replace(value)
+}
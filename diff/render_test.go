@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	// Given the result of a comparison
+	new := entry("b", "a", "c")
+	new.Tracks[2].Artists = []string{"Artist"}
+	new.Tracks[2].AddedBy = "friend"
+	new.Tracks[2].AddedAt = time.Date(2024, 2, 3, 4, 5, 0, 0, time.UTC)
+	result := Compare(entry("a", "b", "d"), new)
+
+	t.Run("it should render a human readable summary", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		Render(buffer, result, OutputText)
+
+		expected := "Playlist: 1 added, 1 removed, 1 reordered\n" +
+			"+ 3 Artist - Song c (added by friend on 2024-02-03 04:05)\n" +
+			"- 3 Song d\n" +
+			"~ 2 -> 1 Song b\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+
+	t.Run("it should render markdown tables", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		Render(buffer, result, OutputMarkdown)
+
+		for _, expected := range []string{
+			"# Playlist\n",
+			"## Added\n\n| # | Track | Added by | Added at |\n|---|---|---|---|\n| 3 | Artist - Song c | friend | 2024-02-03 04:05 |\n",
+			"## Removed\n",
+			"## Reordered\n",
+		} {
+			if !strings.Contains(buffer.String(), expected) {
+				t.Errorf("Expected markdown to contain %q, got\n%s", expected, buffer.String())
+			}
+		}
+	})
+
+	t.Run("it should render json", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		Render(buffer, result, OutputJson)
+
+		var decoded Result
+		if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+			t.Fatalf("Expected valid json: %s", err.Error())
+		}
+		if len(decoded.Added) != 1 || decoded.Added[0].Track.AddedBy != "friend" {
+			t.Errorf("Unexpected decoded result %+v", decoded)
+		}
+	})
+
+	t.Run("it should return an error for unknown outputs", func(t *testing.T) {
+		if err := Render(&bytes.Buffer{}, result, "html"); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package enrich
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Cache keeps looked up values by id across runs in a json file, a nil
+// cache disables caching
+type Cache[T any] struct {
+	path    string
+	entries map[string]T
+	changed bool
+}
+
+// NewCache returns a cache which is only kept in memory
+func NewCache[T any]() *Cache[T] {
+	return &Cache[T]{entries: map[string]T{}}
+}
+
+// LoadCache reads the cache stored at path, a missing file is an empty cache
+func LoadCache[T any](path string) (*Cache[T], error) {
+	cache := &Cache[T]{path: path, entries: map[string]T{}}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache %s: %w", path, err)
+	}
+
+	return cache, nil
+}
+
+func (c *Cache[T]) Get(id string) (T, bool) {
+	if c == nil {
+		var zero T
+		return zero, false
+	}
+
+	value, ok := c.entries[id]
+	return value, ok
+}
+
+func (c *Cache[T]) Put(id string, value T) {
+	if c == nil {
+		return
+	}
+
+	c.entries[id] = value
+	c.changed = true
+}
+
+func (c *Cache[T]) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(c.entries)
+}
+
+// Save writes the cache back to its file when it changed
+func (c *Cache[T]) Save() error {
+	if c == nil || c.path == "" || !c.changed {
+		return nil
+	}
+
+	content, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	c.changed = false
+	return nil
+}
+
+// lookup fetches the ids missing from the cache, without duplicates, in
+// batches of size. Values missing from a response are cached as the zero
+// value so that they are not looked up again.
+func lookup[T any](cache *Cache[T], ids []string, size int, fetch func([]string) ([]T, error)) error {
+	var missing []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if _, ok := cache.Get(id); !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	for start := 0; start < len(missing); start += size {
+		batch := missing[start:min(start+size, len(missing))]
+
+		values, err := fetch(batch)
+		if err != nil {
+			return err
+		}
+
+		for i, id := range batch {
+			var value T
+			if i < len(values) {
+				value = values[i]
+			}
+			cache.Put(id, value)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package enrich
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	t.Run("it should persist the entries", func(t *testing.T) {
+		// Given a cache with an entry
+		path := filepath.Join(t.TempDir(), "cache", "features.json")
+		cache, err := LoadCache[int](path)
+		if err != nil {
+			t.Fatalf("LoadCache returned an error: %s", err.Error())
+		}
+		cache.Put("t1", 42)
+
+		// When saving and loading it again
+		if err := cache.Save(); err != nil {
+			t.Fatalf("Save returned an error: %s", err.Error())
+		}
+		loaded, _ := LoadCache[int](path)
+
+		// Then the entry should be found
+		if value, ok := loaded.Get("t1"); !ok || value != 42 {
+			t.Errorf("Expected 42, got %d (%v)", value, ok)
+		}
+	})
+
+	t.Run("it should be disabled when nil", func(t *testing.T) {
+		var cache *Cache[int]
+
+		cache.Put("t1", 42)
+
+		if _, ok := cache.Get("t1"); ok || cache.Save() != nil {
+			t.Errorf("Expected a nil cache to be empty")
+		}
+	})
+}
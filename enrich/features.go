@@ -0,0 +1,149 @@
+package enrich
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+type FeatureClient interface {
+	AudioFeatures(ids []string) ([]*api.AudioFeatures, error)
+}
+
+// FeatureCache holds the features by track id, nil for tracks without
+// features so that they are not looked up again
+type FeatureCache = Cache[*export.AudioFeatures]
+
+// AudioFeatures sets the audio features of the tracks, looking up the ids
+// missing from the cache in batches of api.MaxItemsPerRequest. Tracks
+// without id, such as local files, and episodes are left untouched.
+func AudioFeatures(client FeatureClient, cache *FeatureCache, tracks []export.Track) error {
+	if cache == nil {
+		cache = NewCache[*export.AudioFeatures]()
+	}
+
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = musicId(track, track.Id)
+	}
+
+	err := lookup(cache, ids, api.MaxItemsPerRequest, func(ids []string) ([]*export.AudioFeatures, error) {
+		batch, err := client.AudioFeatures(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the audio features: %w", err)
+		}
+
+		features := make([]*export.AudioFeatures, len(batch))
+		for i, f := range batch {
+			if f != nil {
+				features[i] = toAudioFeatures(*f)
+			}
+		}
+		return features, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range tracks {
+		if features, ok := cache.Get(ids[i]); ok {
+			tracks[i].AudioFeatures = features
+		}
+	}
+
+	return nil
+}
+
+// FeaturesStage enriches the exported tracks with their audio features,
+// buffering them to look up api.MaxItemsPerRequest tracks per call
+func FeaturesStage(client FeatureClient, cache *FeatureCache) export.Stage {
+	if cache == nil {
+		cache = NewCache[*export.AudioFeatures]()
+	}
+
+	return batchStage(func(tracks []export.Track) error {
+		return AudioFeatures(client, cache, tracks)
+	})
+}
+
+// batchStage returns a stage filling the tracks api.MaxItemsPerRequest at a time
+func batchStage(fill func([]export.Track) error) export.Stage {
+	return func(next export.Exporter) export.Exporter {
+		return &batchExporter{next: next, fill: fill}
+	}
+}
+
+// batchExporter buffers the tracks of a playlist, fills them in batches and
+// forwards them in order
+type batchExporter struct {
+	next   export.Exporter
+	fill   func([]export.Track) error
+	buffer []export.Track
+}
+
+func (b *batchExporter) BeginPlaylist(playlist export.Playlist) error {
+	return b.next.BeginPlaylist(playlist)
+}
+
+func (b *batchExporter) WriteTrack(track export.Track) error {
+	b.buffer = append(b.buffer, track)
+	if len(b.buffer) < api.MaxItemsPerRequest {
+		return nil
+	}
+
+	return b.flush()
+}
+
+func (b *batchExporter) EndPlaylist() error {
+	if err := b.flush(); err != nil {
+		return err
+	}
+
+	return b.next.EndPlaylist()
+}
+
+func (b *batchExporter) Close() error {
+	return b.next.Close()
+}
+
+func (b *batchExporter) flush() error {
+	if len(b.buffer) == 0 {
+		return nil
+	}
+
+	if err := b.fill(b.buffer); err != nil {
+		return err
+	}
+	for _, track := range b.buffer {
+		if err := b.next.WriteTrack(track); err != nil {
+			return err
+		}
+	}
+
+	b.buffer = b.buffer[:0]
+	return nil
+}
+
+// musicId returns the id unless the track is an episode, whose show is
+// neither an album nor has audio features
+func musicId(track export.Track, id string) string {
+	if track.Type == export.TypeEpisode {
+		return ""
+	}
+
+	return id
+}
+
+func toAudioFeatures(features api.AudioFeatures) *export.AudioFeatures {
+	return &export.AudioFeatures{
+		Danceability:  features.Danceability,
+		Energy:        features.Energy,
+		Key:           features.Key,
+		Mode:          features.Mode,
+		Tempo:         features.Tempo,
+		Valence:       features.Valence,
+		Loudness:      features.Loudness,
+		TimeSignature: features.TimeSignature,
+	}
+}
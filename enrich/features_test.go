@@ -0,0 +1,133 @@
+package enrich
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Feature Client knowing every track but "unknown"
+type mockFeatureClient struct {
+	calls [][]string
+}
+
+func (m *mockFeatureClient) AudioFeatures(ids []string) ([]*api.AudioFeatures, error) {
+	m.calls = append(m.calls, ids)
+
+	features := make([]*api.AudioFeatures, len(ids))
+	for i, id := range ids {
+		if id != "unknown" {
+			features[i] = &api.AudioFeatures{Id: id, Tempo: 100, Key: 5}
+		}
+	}
+	return features, nil
+}
+
+// Mock Exporter recording the tracks
+type mockExporter struct {
+	tracks []export.Track
+}
+
+func (m *mockExporter) BeginPlaylist(export.Playlist) error { return nil }
+func (m *mockExporter) EndPlaylist() error                  { return nil }
+func (m *mockExporter) Close() error                        { return nil }
+
+func (m *mockExporter) WriteTrack(track export.Track) error {
+	m.tracks = append(m.tracks, track)
+	return nil
+}
+
+func TestAudioFeatures(t *testing.T) {
+	t.Run("it should look up the missing ids once", func(t *testing.T) {
+		// Given tracks with a duplicate, a local file and a cached track
+		client := &mockFeatureClient{}
+		cache := &FeatureCache{entries: map[string]*export.AudioFeatures{"cached": {Tempo: 90}}}
+		tracks := []export.Track{{Id: "t1"}, {Id: "t1"}, {Id: ""}, {Id: "cached"}, {Id: "unknown"}}
+
+		// When enriching them
+		err := AudioFeatures(client, cache, tracks)
+
+		// Then a single call should be made for the missing ids
+		if err != nil || len(client.calls) != 1 || len(client.calls[0]) != 2 {
+			t.Fatalf("Unexpected calls %v, %v", client.calls, err)
+		}
+
+		// and every known track should have its features
+		if tracks[1].AudioFeatures.Tempo != 100 || tracks[3].AudioFeatures.Tempo != 90 {
+			t.Errorf("Unexpected features %+v", tracks)
+		}
+		if tracks[2].AudioFeatures != nil || tracks[4].AudioFeatures != nil {
+			t.Errorf("Expected no features for unknown tracks")
+		}
+
+		// and unknown tracks should be cached as well
+		if _, ok := cache.Get("unknown"); !ok {
+			t.Errorf("Expected unknown tracks to be cached")
+		}
+	})
+
+	t.Run("it should not look up the episodes", func(t *testing.T) {
+		client := &mockFeatureClient{}
+		tracks := []export.Track{{Id: "e1", Type: export.TypeEpisode}}
+
+		err := AudioFeatures(client, nil, tracks)
+
+		if err != nil || len(client.calls) != 0 || tracks[0].AudioFeatures != nil {
+			t.Errorf("Unexpected calls %v, %v", client.calls, err)
+		}
+	})
+}
+
+func TestFeaturesStage(t *testing.T) {
+	t.Run("it should enrich the tracks in batches", func(t *testing.T) {
+		// Given a playlist of 250 tracks
+		client := &mockFeatureClient{}
+		next := &mockExporter{}
+		stage := FeaturesStage(client, nil)(next)
+
+		// When writing it through the stage
+		stage.BeginPlaylist(export.Playlist{Id: "p1"})
+		for i := range 250 {
+			stage.WriteTrack(export.Track{Position: i, Id: fmt.Sprintf("t%d", i)})
+		}
+		stage.EndPlaylist()
+
+		// Then the features should be looked up 100 tracks at a time
+		if len(client.calls) != 3 || len(client.calls[2]) != 50 {
+			t.Errorf("Unexpected calls %v", client.calls)
+		}
+
+		// and every track should be forwarded in order with its features
+		if len(next.tracks) != 250 {
+			t.Fatalf("Expected 250 tracks, got %d", len(next.tracks))
+		}
+		for i, track := range next.tracks {
+			if track.Position != i || track.AudioFeatures == nil {
+				t.Fatalf("Unexpected track %+v at %d", track, i)
+			}
+		}
+	})
+
+	t.Run("it should fill the optional csv columns", func(t *testing.T) {
+		// Given a csv export with the tempo column
+		format, _ := export.ParseFormat("csv")
+		options := export.Options{Columns: []string{"tempo"}, Stages: []export.Stage{FeaturesStage(&mockFeatureClient{}, nil)}}
+		out := &bytes.Buffer{}
+		exporter := format.New(out, options)
+
+		// When writing a track through the stage
+		stage := options.Stages[0](exporter)
+		stage.BeginPlaylist(export.Playlist{Id: "p1"})
+		stage.WriteTrack(export.Track{Id: "t1"})
+		stage.EndPlaylist()
+		stage.Close()
+
+		// Then the tempo should be exported
+		if !bytes.Contains(out.Bytes(), []byte(",100\n")) {
+			t.Errorf("Unexpected output %s", out.String())
+		}
+	})
+}
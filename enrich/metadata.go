@@ -0,0 +1,151 @@
+package enrich
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+type ArtistClient interface {
+	Artists(ids []string) ([]*api.Artist, error)
+}
+
+type AlbumClient interface {
+	Albums(ids []string) ([]*api.Album, error)
+}
+
+type ArtistCache = Cache[*export.ArtistDetails]
+
+type AlbumCache = Cache[*export.AlbumDetails]
+
+// Artists sets the details of the artists of the tracks, looking up every
+// artist once in batches of api.MaxArtistsPerRequest. Unknown artists are
+// left out of the details.
+func Artists(client ArtistClient, cache *ArtistCache, tracks []export.Track) error {
+	if cache == nil {
+		cache = NewCache[*export.ArtistDetails]()
+	}
+
+	var ids []string
+	for _, track := range tracks {
+		ids = append(ids, track.ArtistIds...)
+	}
+
+	err := lookup(cache, ids, api.MaxArtistsPerRequest, func(ids []string) ([]*export.ArtistDetails, error) {
+		artists, err := client.Artists(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the artists: %w", err)
+		}
+
+		details := make([]*export.ArtistDetails, len(artists))
+		for i, artist := range artists {
+			if artist != nil {
+				details[i] = toArtistDetails(*artist)
+			}
+		}
+		return details, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range tracks {
+		tracks[i].ArtistDetails = nil
+		for _, id := range tracks[i].ArtistIds {
+			if details, _ := cache.Get(id); details != nil {
+				tracks[i].ArtistDetails = append(tracks[i].ArtistDetails, *details)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Albums sets the details of the albums of the tracks, looking up every
+// album once in batches of api.MaxAlbumsPerRequest. Episodes are left untouched.
+func Albums(client AlbumClient, cache *AlbumCache, tracks []export.Track) error {
+	if cache == nil {
+		cache = NewCache[*export.AlbumDetails]()
+	}
+
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = musicId(track, track.AlbumId)
+	}
+
+	err := lookup(cache, ids, api.MaxAlbumsPerRequest, func(ids []string) ([]*export.AlbumDetails, error) {
+		albums, err := client.Albums(ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the albums: %w", err)
+		}
+
+		details := make([]*export.AlbumDetails, len(albums))
+		for i, album := range albums {
+			if album != nil {
+				details[i] = toAlbumDetails(*album)
+			}
+		}
+		return details, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range tracks {
+		if details, ok := cache.Get(ids[i]); ok {
+			tracks[i].AlbumDetails = details
+		}
+	}
+
+	return nil
+}
+
+// ArtistsStage enriches the exported tracks with their full artists, the
+// cache is shared by every playlist so that each artist is fetched once
+func ArtistsStage(client ArtistClient, cache *ArtistCache) export.Stage {
+	if cache == nil {
+		cache = NewCache[*export.ArtistDetails]()
+	}
+
+	return batchStage(func(tracks []export.Track) error {
+		return Artists(client, cache, tracks)
+	})
+}
+
+// AlbumsStage enriches the exported tracks with their full albums, the
+// cache is shared by every playlist so that each album is fetched once
+func AlbumsStage(client AlbumClient, cache *AlbumCache) export.Stage {
+	if cache == nil {
+		cache = NewCache[*export.AlbumDetails]()
+	}
+
+	return batchStage(func(tracks []export.Track) error {
+		return Albums(client, cache, tracks)
+	})
+}
+
+func toArtistDetails(artist api.Artist) *export.ArtistDetails {
+	return &export.ArtistDetails{
+		Id:         artist.Id,
+		Genres:     artist.Genres,
+		Followers:  artist.Followers.Total,
+		Popularity: artist.Popularity,
+	}
+}
+
+func toAlbumDetails(album api.Album) *export.AlbumDetails {
+	copyrights := make([]string, 0, len(album.Copyrights))
+	for _, copyright := range album.Copyrights {
+		copyrights = append(copyrights, copyright.Text)
+	}
+
+	return &export.AlbumDetails{
+		Id:                   album.Id,
+		Label:                album.Label,
+		ReleaseDate:          album.ReleaseDate,
+		ReleaseDatePrecision: album.ReleaseDatePrecision,
+		Upc:                  album.ExternalIds.Upc,
+		Copyrights:           copyrights,
+	}
+}
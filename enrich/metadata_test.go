@@ -0,0 +1,88 @@
+package enrich
+
+import (
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Artist and Album Client
+type mockMetadataClient struct {
+	artistCalls [][]string
+	albumCalls  [][]string
+}
+
+func (m *mockMetadataClient) Artists(ids []string) ([]*api.Artist, error) {
+	m.artistCalls = append(m.artistCalls, ids)
+
+	artists := make([]*api.Artist, len(ids))
+	for i, id := range ids {
+		if id != "unknown" {
+			artists[i] = &api.Artist{SimplifiedArtist: api.SimplifiedArtist{Id: id}, Genres: []string{"genre " + id}}
+		}
+	}
+	return artists, nil
+}
+
+func (m *mockMetadataClient) Albums(ids []string) ([]*api.Album, error) {
+	m.albumCalls = append(m.albumCalls, ids)
+
+	albums := make([]*api.Album, len(ids))
+	for i, id := range ids {
+		albums[i] = &api.Album{Label: "label " + id, Copyrights: []api.Copyright{{Text: "(C) " + id}}}
+	}
+	return albums, nil
+}
+
+func TestArtists(t *testing.T) {
+	t.Run("it should look up every artist once", func(t *testing.T) {
+		// Given tracks sharing an artist
+		client := &mockMetadataClient{}
+		tracks := []export.Track{
+			{Id: "t1", ArtistIds: []string{"a1", "a2"}},
+			{Id: "t2", ArtistIds: []string{"a2", "unknown"}},
+		}
+
+		// When enriching them
+		err := Artists(client, nil, tracks)
+
+		// Then a single call should be made with the distinct ids
+		if err != nil || len(client.artistCalls) != 1 || len(client.artistCalls[0]) != 3 {
+			t.Fatalf("Unexpected calls %v, %v", client.artistCalls, err)
+		}
+
+		// and the known artists should be set
+		if len(tracks[0].ArtistDetails) != 2 || len(tracks[1].ArtistDetails) != 1 || tracks[1].ArtistDetails[0].Genres[0] != "genre a2" {
+			t.Errorf("Unexpected details %+v", tracks)
+		}
+	})
+}
+
+func TestAlbumsStage(t *testing.T) {
+	t.Run("it should share the lookups across playlists", func(t *testing.T) {
+		// Given two playlists with tracks of the same album
+		client := &mockMetadataClient{}
+		next := &mockExporter{}
+		stage := AlbumsStage(client, nil)(next)
+
+		// When writing them through the stage
+		for _, id := range []string{"p1", "p2"} {
+			stage.BeginPlaylist(export.Playlist{Id: id})
+			stage.WriteTrack(export.Track{Id: "t1", AlbumId: "b1"})
+			stage.EndPlaylist()
+		}
+
+		// Then the album should be fetched once
+		if len(client.albumCalls) != 1 {
+			t.Errorf("Expected a single call, got %v", client.albumCalls)
+		}
+
+		// and set on both tracks
+		for _, track := range next.tracks {
+			if track.AlbumDetails == nil || track.AlbumDetails.Label != "label b1" || track.AlbumDetails.Copyrights[0] != "(C) b1" {
+				t.Errorf("Unexpected details %+v", track.AlbumDetails)
+			}
+		}
+	})
+}
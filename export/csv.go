@@ -0,0 +1,98 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(Format{
+		Name:        "csv",
+		Extension:   "csv",
+		Description: "Comma separated values, one track per row",
+		New:         func(w io.Writer, o Options) Exporter { return NewCsvWriter(w, o.Columns...) },
+	})
+}
+
+var csvHeader = []string{
+	"playlist_id", "playlist_name", "position", "id", "uri", "name", "artists", "album",
+	"release_date", "duration_ms", "isrc", "explicit", "popularity", "added_at", "added_by",
+}
+
+// CsvWriter writes a row per track, artists are separated by a semicolon.
+// The optional columns are appended after the regular ones.
+type CsvWriter struct {
+	writer        *csv.Writer
+	columns       []string
+	playlist      Playlist
+	headerWritten bool
+}
+
+func NewCsvWriter(writer io.Writer, columns ...string) *CsvWriter {
+	return &CsvWriter{writer: csv.NewWriter(writer), columns: columns}
+}
+
+func (c *CsvWriter) BeginPlaylist(playlist Playlist) error {
+	c.playlist = playlist
+	return c.writeHeader()
+}
+
+func (c *CsvWriter) WriteTrack(track Track) error {
+	addedAt := ""
+	if !track.AddedAt.IsZero() {
+		addedAt = track.AddedAt.Format(time.RFC3339)
+	}
+
+	row := []string{
+		c.playlist.Id,
+		c.playlist.Name,
+		strconv.Itoa(track.Position),
+		track.Id,
+		track.Uri,
+		track.Name,
+		strings.Join(track.Artists, ";"),
+		track.Album,
+		track.ReleaseDate,
+		strconv.Itoa(track.DurationMs),
+		track.Isrc,
+		strconv.FormatBool(track.Explicit),
+		strconv.Itoa(track.Popularity),
+		addedAt,
+		track.AddedBy,
+	}
+	for _, column := range c.columns {
+		value := ""
+		if columnValue, ok := optionalColumns[column]; ok {
+			value = columnValue(track)
+		}
+		row = append(row, value)
+	}
+
+	return c.writer.Write(row)
+}
+
+func (c *CsvWriter) EndPlaylist() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *CsvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *CsvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+
+	return c.writer.Write(append(append([]string{}, csvHeader...), c.columns...))
+}
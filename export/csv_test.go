@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestCsvWriter(t *testing.T) {
+	t.Run("it should write a header and a row per track", func(t *testing.T) {
+		// Given a source with two playlists
+		source := createSource()
+
+		// When exporting it as csv
+		buffer := &bytes.Buffer{}
+		if err := Export(source, mustFormat(t, "csv"), buffer, Options{}); err != nil {
+			t.Fatalf("Export returned an error: %s", err.Error())
+		}
+
+		// Then the output should be valid csv
+		rows, err := csv.NewReader(buffer).ReadAll()
+		if err != nil {
+			t.Fatalf("Expected valid csv: %s", err.Error())
+		}
+
+		// with a single header and a row per track
+		if len(rows) != 4 {
+			t.Fatalf("Expected 4 rows, got %d", len(rows))
+		}
+		if rows[0][0] != "playlist_id" {
+			t.Errorf("Expected the header first, got %v", rows[0])
+		}
+
+		expected := []string{
+			"p1", "First", "1", "t2", "", "Song 2", "Artist 1;Artist 2", "",
+			"", "200000", "", "false", "0", "2024-05-01T10:00:00Z", "",
+		}
+		for i, value := range expected {
+			if rows[2][i] != value {
+				t.Errorf("Expected column %s to be '%s', got '%s'", rows[0][i], value, rows[2][i])
+			}
+		}
+	})
+
+	t.Run("it should append the selected optional columns", func(t *testing.T) {
+		// Given a source with a track having audio features
+		source := createSource()
+		source.tracks["p2"][0].AudioFeatures = &AudioFeatures{Tempo: 120.5, Key: 7}
+
+		// When exporting it with the tempo and key columns
+		buffer := &bytes.Buffer{}
+		Export(source, mustFormat(t, "csv"), buffer, Options{Columns: []string{"tempo", "key"}})
+
+		// Then the columns should be appended, empty when unknown
+		rows, _ := csv.NewReader(buffer).ReadAll()
+		last := len(rows[0]) - 1
+		if rows[0][last-1] != "tempo" || rows[0][last] != "key" {
+			t.Errorf("Unexpected header %v", rows[0])
+		}
+		if rows[1][last-1] != "" || rows[3][last-1] != "120.5" || rows[3][last] != "7" {
+			t.Errorf("Unexpected values %v and %v", rows[1], rows[3])
+		}
+	})
+	t.Run("it should export the genres and label columns", func(t *testing.T) {
+		// Given a track with full artists and album
+		source := createSource()
+		source.tracks["p2"][0].ArtistDetails = []ArtistDetails{{Genres: []string{"rock", "pop"}}, {Genres: []string{"rock", "indie"}}}
+		source.tracks["p2"][0].AlbumDetails = &AlbumDetails{Label: "Label"}
+
+		// When exporting it with the genres and label columns
+		buffer := &bytes.Buffer{}
+		Export(source, mustFormat(t, "csv"), buffer, Options{Columns: []string{"genres", "label"}})
+
+		// Then the distinct genres and the label should be exported
+		rows, _ := csv.NewReader(buffer).ReadAll()
+		last := len(rows[0]) - 1
+		if rows[3][last-1] != "rock;pop;indie" || rows[3][last] != "Label" {
+			t.Errorf("Unexpected values %v", rows[3])
+		}
+	})
+}
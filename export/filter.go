@@ -0,0 +1,27 @@
+package export
+
+// Exclude returns a stage dropping the tracks of the given types, their
+// position being left untouched
+func Exclude(types ...string) Stage {
+	excluded := map[string]bool{}
+	for _, kind := range types {
+		excluded[kind] = true
+	}
+
+	return func(next Exporter) Exporter {
+		return &excludeExporter{Exporter: next, excluded: excluded}
+	}
+}
+
+type excludeExporter struct {
+	Exporter
+	excluded map[string]bool
+}
+
+func (e *excludeExporter) WriteTrack(track Track) error {
+	if e.excluded[track.Type] {
+		return nil
+	}
+
+	return e.Exporter.WriteTrack(track)
+}
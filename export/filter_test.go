@@ -0,0 +1,33 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExclude(t *testing.T) {
+	t.Run("it should drop the excluded types and keep the positions", func(t *testing.T) {
+		// Given a playlist with a track, an episode and a local file
+		source := mockSource{
+			playlists: []Playlist{{Id: "p1", Name: "Mixed"}},
+			tracks: map[string][]Track{"p1": {
+				{Position: 0, Type: TypeTrack, Id: "t1"},
+				{Position: 1, Type: TypeEpisode, Id: "e1"},
+				{Position: 2, Type: TypeLocal, Uri: "spotify:local:a:b:c:1"},
+			}},
+		}
+
+		// When excluding the episodes
+		buffer := &bytes.Buffer{}
+		err := Export(source, mustFormat(t, "ndjson"), buffer, Options{Stages: []Stage{Exclude(TypeEpisode)}})
+
+		// Then the track and the local file should be exported
+		if err != nil || strings.Count(buffer.String(), "\n") != 2 || strings.Contains(buffer.String(), `"e1"`) {
+			t.Fatalf("Unexpected output %s, %v", buffer.String(), err)
+		}
+		if !strings.Contains(buffer.String(), `"position":2,"type":"local"`) {
+			t.Errorf("Expected the local file at its position, got %s", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register(Format{
+		Name:        "json",
+		Extension:   "json",
+		Description: "Single json document with the tracks nested in their playlist",
+		New:         func(w io.Writer, _ Options) Exporter { return NewJsonWriter(w) },
+	})
+}
+
+// JsonWriter writes a Document incrementally, so that only the track being
+// written is held in memory
+type JsonWriter struct {
+	writer        io.Writer
+	started       bool
+	firstPlaylist bool
+	firstTrack    bool
+}
+
+func NewJsonWriter(writer io.Writer) *JsonWriter {
+	return &JsonWriter{writer: writer, firstPlaylist: true}
+}
+
+func (j *JsonWriter) BeginPlaylist(playlist Playlist) error {
+	if err := j.start(); err != nil {
+		return err
+	}
+
+	metadata, err := json.Marshal(playlist)
+	if err != nil {
+		return fmt.Errorf("failed to marshal playlist %s: %w", playlist.Id, err)
+	}
+
+	separator := ","
+	if j.firstPlaylist {
+		separator = ""
+	}
+	j.firstPlaylist = false
+	j.firstTrack = true
+
+	return j.write(separator + `{"playlist":` + string(metadata) + `,"tracks":[`)
+}
+
+func (j *JsonWriter) WriteTrack(track Track) error {
+	encoded, err := json.Marshal(track)
+	if err != nil {
+		return fmt.Errorf("failed to marshal track %s: %w", track.Id, err)
+	}
+
+	if !j.firstTrack {
+		encoded = append([]byte{','}, encoded...)
+	}
+	j.firstTrack = false
+
+	_, err = j.writer.Write(encoded)
+	return err
+}
+
+func (j *JsonWriter) EndPlaylist() error {
+	return j.write("]}")
+}
+
+// Close terminates the document, it does not close the underlying writer
+func (j *JsonWriter) Close() error {
+	if err := j.start(); err != nil {
+		return err
+	}
+
+	return j.write("]}\n")
+}
+
+func (j *JsonWriter) start() error {
+	if j.started {
+		return nil
+	}
+	j.started = true
+
+	return j.write(fmt.Sprintf(`{"schema_version":%d,"playlists":[`, SchemaVersion))
+}
+
+func (j *JsonWriter) write(s string) error {
+	_, err := io.WriteString(j.writer, s)
+	return err
+}
+
+func ReadDocument(reader io.Reader) (*Document, error) {
+	var document Document
+	if err := json.NewDecoder(reader).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	if document.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", document.SchemaVersion)
+	}
+
+	return &document, nil
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJsonWriter(t *testing.T) {
+	t.Run("it should write a valid document with nested tracks", func(t *testing.T) {
+		// Given a source with two playlists
+		source := createSource()
+
+		// When exporting it as json
+		buffer := &bytes.Buffer{}
+		if err := Export(source, mustFormat(t, "json"), buffer, Options{}); err != nil {
+			t.Fatalf("Export returned an error: %s", err.Error())
+		}
+
+		// Then the output should be decodable as a document
+		var document Document
+		if err := json.Unmarshal(buffer.Bytes(), &document); err != nil {
+			t.Fatalf("Expected a valid json document, got %s: %s", err.Error(), buffer.String())
+		}
+
+		// and carry the schema version
+		if document.SchemaVersion != SchemaVersion {
+			t.Errorf("Expected schema version %d, got %d", SchemaVersion, document.SchemaVersion)
+		}
+
+		// and nest the tracks in their playlist
+		if len(document.Playlists) != 2 {
+			t.Fatalf("Expected 2 playlists, got %d", len(document.Playlists))
+		}
+		if len(document.Playlists[0].Tracks) != 2 || len(document.Playlists[1].Tracks) != 1 {
+			t.Errorf("Tracks were not nested correctly: %+v", document.Playlists)
+		}
+		if document.Playlists[0].Tracks[1].Artists[1] != "Artist 2" {
+			t.Errorf("Expected second artist to be 'Artist 2', got %v", document.Playlists[0].Tracks[1].Artists)
+		}
+	})
+
+	t.Run("it should write an empty document when no playlist is exported", func(t *testing.T) {
+		// Given a json writer
+		buffer := &bytes.Buffer{}
+		writer := NewJsonWriter(buffer)
+
+		// When closing it straight away
+		if err := writer.Close(); err != nil {
+			t.Fatalf("Close returned an error: %s", err.Error())
+		}
+
+		// Then an empty document should be written
+		expected := `{"schema_version":1,"playlists":[]}` + "\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, buffer.String())
+		}
+	})
+}
+
+func TestReadDocument(t *testing.T) {
+	t.Run("it should read back a json export", func(t *testing.T) {
+		// Given a json export
+		buffer := &bytes.Buffer{}
+		Export(createSource(), mustFormat(t, "json"), buffer, Options{})
+
+		// When reading it
+		document, err := ReadDocument(buffer)
+
+		// Then the playlists should be returned
+		if err != nil {
+			t.Fatalf("ReadDocument returned an error: %s", err.Error())
+		}
+		if len(document.Playlists) != 2 || document.Playlists[1].Tracks[0].Id != "t3" {
+			t.Errorf("Unexpected document %+v", document)
+		}
+	})
+
+	t.Run("it should refuse newer schema versions", func(t *testing.T) {
+		_, err := ReadDocument(strings.NewReader(`{"schema_version": 99, "playlists": []}`))
+
+		if err == nil || err.Error() != "unsupported schema version 99" {
+			t.Errorf("Expected an unsupported schema error, got %v", err)
+		}
+	})
+}
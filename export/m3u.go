@@ -0,0 +1,57 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:           "m3u8",
+		Extension:      "m3u8",
+		Description:    "Extended M3U playlist",
+		SinglePlaylist: true,
+		New:            func(w io.Writer, _ Options) Exporter { return NewM3uWriter(w) },
+	})
+}
+
+// M3uWriter writes an extended M3U playlist, it expects a single playlist
+// per writer
+type M3uWriter struct {
+	writer io.Writer
+}
+
+func NewM3uWriter(writer io.Writer) *M3uWriter {
+	return &M3uWriter{writer: writer}
+}
+
+func (m *M3uWriter) BeginPlaylist(playlist Playlist) error {
+	_, err := fmt.Fprintf(m.writer, "#EXTM3U\n#PLAYLIST:%s\n", singleLine(playlist.Name))
+	return err
+}
+
+func (m *M3uWriter) WriteTrack(track Track) error {
+	_, err := fmt.Fprintf(
+		m.writer,
+		"#EXTINF:%d,%s - %s\n%s\n",
+		(track.DurationMs+500)/1000,
+		singleLine(strings.Join(track.Artists, ", ")),
+		singleLine(track.Name),
+		track.Uri,
+	)
+	return err
+}
+
+func (m *M3uWriter) EndPlaylist() error {
+	return nil
+}
+
+func (m *M3uWriter) Close() error {
+	return nil
+}
+
+// singleLine prevents names from breaking the line based M3U format
+func singleLine(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestM3uWriter(t *testing.T) {
+	t.Run("it should write an extended m3u playlist", func(t *testing.T) {
+		// Given a m3u writer
+		buffer := &bytes.Buffer{}
+		writer := NewM3uWriter(buffer)
+
+		// When writing a playlist
+		writer.BeginPlaylist(Playlist{Id: "p1", Name: "Road\ntrip"})
+		writer.WriteTrack(Track{
+			Uri:        "spotify:track:t1",
+			Name:       "Song",
+			Artists:    []string{"Artist 1", "Artist 2"},
+			DurationMs: 180499,
+		})
+		writer.EndPlaylist()
+		writer.Close()
+
+		// Then the EXTINF entry should carry duration in seconds and "Artist - Title"
+		expected := "#EXTM3U\n" +
+			"#PLAYLIST:Road trip\n" +
+			"#EXTINF:180,Artist 1, Artist 2 - Song\n" +
+			"spotify:track:t1\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func init() {
+	Register(Format{
+		Name:        "ndjson",
+		Extension:   "ndjson",
+		Description: "Newline delimited json, one track per line",
+		New:         func(w io.Writer, _ Options) Exporter { return NewNdjsonWriter(w) },
+	})
+}
+
+// NdjsonWriter writes one Record per line, making the output suitable for
+// streaming consumers
+type NdjsonWriter struct {
+	encoder  *json.Encoder
+	playlist Playlist
+}
+
+func NewNdjsonWriter(writer io.Writer) *NdjsonWriter {
+	return &NdjsonWriter{encoder: json.NewEncoder(writer)}
+}
+
+func (n *NdjsonWriter) BeginPlaylist(playlist Playlist) error {
+	n.playlist = playlist
+	return nil
+}
+
+func (n *NdjsonWriter) WriteTrack(track Track) error {
+	return n.encoder.Encode(Record{
+		SchemaVersion: SchemaVersion,
+		PlaylistId:    n.playlist.Id,
+		PlaylistName:  n.playlist.Name,
+		Track:         track,
+	})
+}
+
+func (n *NdjsonWriter) EndPlaylist() error {
+	n.playlist = Playlist{}
+	return nil
+}
+
+func (n *NdjsonWriter) Close() error {
+	return nil
+}
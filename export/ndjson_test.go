@@ -0,0 +1,45 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestNdjsonWriter(t *testing.T) {
+	t.Run("it should write a record per line", func(t *testing.T) {
+		// Given a source with two playlists
+		source := createSource()
+
+		// When exporting it as ndjson
+		buffer := &bytes.Buffer{}
+		if err := Export(source, mustFormat(t, "ndjson"), buffer, Options{}); err != nil {
+			t.Fatalf("Export returned an error: %s", err.Error())
+		}
+
+		// Then every line should be a record referencing its playlist
+		expectedPlaylists := []string{"p1", "p1", "p2"}
+		scanner := bufio.NewScanner(buffer)
+		line := 0
+		for scanner.Scan() {
+			var record Record
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				t.Fatalf("Line %d is not valid json: %s", line, err.Error())
+			}
+
+			if record.SchemaVersion != SchemaVersion {
+				t.Errorf("Expected schema version %d, got %d", SchemaVersion, record.SchemaVersion)
+			}
+			if record.PlaylistId != expectedPlaylists[line] {
+				t.Errorf("Expected playlist %s on line %d, got %s", expectedPlaylists[line], line, record.PlaylistId)
+			}
+
+			line++
+		}
+
+		if line != len(expectedPlaylists) {
+			t.Errorf("Expected %d lines, got %d", len(expectedPlaylists), line)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Options selects the optional content of an export
+type Options struct {
+	// Columns are the optional columns added to tabular formats
+	Columns []string
+	// Stages process the tracks before they are written, in order
+	Stages []Stage
+}
+
+// Stage wraps an exporter, e.g. to enrich the tracks before forwarding them
+type Stage func(next Exporter) Exporter
+
+func (o Options) wrap(out Exporter) Exporter {
+	for i := len(o.Stages) - 1; i >= 0; i-- {
+		out = o.Stages[i](out)
+	}
+
+	return out
+}
+
+// optionalColumns are the columns which are only exported on request,
+// their value is empty when the track was not enriched
+var optionalColumns = map[string]func(Track) string{
+	"type": func(t Track) string { return t.Type },
+
+	"danceability":   featureColumn(func(f *AudioFeatures) float64 { return f.Danceability }),
+	"energy":         featureColumn(func(f *AudioFeatures) float64 { return f.Energy }),
+	"key":            featureColumn(func(f *AudioFeatures) float64 { return float64(f.Key) }),
+	"mode":           featureColumn(func(f *AudioFeatures) float64 { return float64(f.Mode) }),
+	"tempo":          featureColumn(func(f *AudioFeatures) float64 { return f.Tempo }),
+	"valence":        featureColumn(func(f *AudioFeatures) float64 { return f.Valence }),
+	"loudness":       featureColumn(func(f *AudioFeatures) float64 { return f.Loudness }),
+	"time_signature": featureColumn(func(f *AudioFeatures) float64 { return float64(f.TimeSignature) }),
+
+	"genres":            genresColumn,
+	"artist_followers":  artistColumn(func(a ArtistDetails) int { return a.Followers }),
+	"artist_popularity": artistColumn(func(a ArtistDetails) int { return a.Popularity }),
+
+	"label":                  albumColumn(func(a *AlbumDetails) string { return a.Label }),
+	"release_date_precision": albumColumn(func(a *AlbumDetails) string { return a.ReleaseDatePrecision }),
+	"upc":                    albumColumn(func(a *AlbumDetails) string { return a.Upc }),
+	"copyrights":             albumColumn(func(a *AlbumDetails) string { return strings.Join(a.Copyrights, ";") }),
+}
+
+// AudioFeatureColumns are the optional columns filled by the audio features
+var AudioFeatureColumns = []string{"danceability", "energy", "key", "mode", "tempo", "valence", "loudness", "time_signature"}
+
+// ArtistColumns are the optional columns filled by the full artists, the
+// followers and popularity are the ones of the first artist
+var ArtistColumns = []string{"genres", "artist_followers", "artist_popularity"}
+
+// AlbumColumns are the optional columns filled by the full album
+var AlbumColumns = []string{"label", "release_date_precision", "upc", "copyrights"}
+
+// OptionalColumns returns the names of the optional columns, sorted
+func OptionalColumns() []string {
+	names := make([]string, 0, len(optionalColumns))
+	for name := range optionalColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseColumns parses a comma separated list of optional columns
+func ParseColumns(value string) ([]string, error) {
+	var columns []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := optionalColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %s, available columns: %s", name, strings.Join(OptionalColumns(), ", "))
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, nil
+}
+
+func featureColumn(value func(*AudioFeatures) float64) func(Track) string {
+	return func(track Track) string {
+		if track.AudioFeatures == nil {
+			return ""
+		}
+
+		return strconv.FormatFloat(value(track.AudioFeatures), 'f', -1, 64)
+	}
+}
+
+// genresColumn joins the genres of every artist of the track, without duplicates
+func genresColumn(track Track) string {
+	var genres []string
+	for _, artist := range track.ArtistDetails {
+		for _, genre := range artist.Genres {
+			if !slices.Contains(genres, genre) {
+				genres = append(genres, genre)
+			}
+		}
+	}
+
+	return strings.Join(genres, ";")
+}
+
+func artistColumn(value func(ArtistDetails) int) func(Track) string {
+	return func(track Track) string {
+		if len(track.ArtistDetails) == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(value(track.ArtistDetails[0]))
+	}
+}
+
+func albumColumn(value func(*AlbumDetails) string) func(Track) string {
+	return func(track Track) string {
+		if track.AlbumDetails == nil {
+			return ""
+		}
+
+		return value(track.AlbumDetails)
+	}
+}
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Mock Stage renaming the tracks
+type renameStage struct {
+	Exporter
+	suffix string
+}
+
+func (r renameStage) WriteTrack(track Track) error {
+	track.Name += r.suffix
+	return r.Exporter.WriteTrack(track)
+}
+
+func TestParseColumns(t *testing.T) {
+	t.Run("it should parse a list of optional columns", func(t *testing.T) {
+		columns, err := ParseColumns("tempo, energy,,key")
+
+		if err != nil || !reflect.DeepEqual(columns, []string{"tempo", "energy", "key"}) {
+			t.Errorf("Unexpected columns %v, %v", columns, err)
+		}
+	})
+
+	t.Run("it should return an error for unknown columns", func(t *testing.T) {
+		if _, err := ParseColumns("tempo,bpm"); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+func TestStages(t *testing.T) {
+	t.Run("it should apply the stages in order", func(t *testing.T) {
+		// Given two stages
+		stage := func(suffix string) Stage {
+			return func(next Exporter) Exporter { return renameStage{next, suffix} }
+		}
+		options := Options{Stages: []Stage{stage(" A"), stage(" B")}}
+
+		// When exporting through them
+		buffer := &bytes.Buffer{}
+		Export(createSource(), mustFormat(t, "ndjson"), buffer, options)
+
+		// Then every track should go through both, in order
+		if !bytes.Contains(buffer.Bytes(), []byte(`"name":"Song 1 A B"`)) {
+			t.Errorf("Unexpected output %s", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// PlaylistSource provides the playlists to export, tracks are streamed one
+// at a time through the handle callback
+type PlaylistSource interface {
+	Playlists() ([]Playlist, error)
+	Tracks(playlist Playlist, handle func(Track) error) error
+}
+
+// Export writes every playlist of the source to the writer using the given
+// format, which must hold several playlists unless the source has only one
+func Export(source PlaylistSource, format Format, writer io.Writer, options Options) error {
+	playlists, err := source.Playlists()
+	if err != nil {
+		return fmt.Errorf("failed to list playlists: %w", err)
+	}
+	if format.SinglePlaylist && len(playlists) > 1 {
+		return fmt.Errorf("format %s supports a single playlist per file", format.Name)
+	}
+
+	out := options.wrap(format.New(writer, options))
+	for _, playlist := range playlists {
+		if err := exportPlaylist(source, playlist, out); err != nil {
+			return err
+		}
+	}
+
+	return out.Close()
+}
+
+// ExportToDirectory writes every playlist of the source to its own file in
+// the given directory
+func ExportToDirectory(source PlaylistSource, format Format, directory string, options Options) error {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", directory, err)
+	}
+
+	playlists, err := source.Playlists()
+	if err != nil {
+		return fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	for _, playlist := range playlists {
+		file, err := os.Create(filepath.Join(directory, FileName(playlist, format)))
+		if err != nil {
+			return fmt.Errorf("failed to create file for playlist %s: %w", playlist.Id, err)
+		}
+
+		out := options.wrap(format.New(file, options))
+		err = exportPlaylist(source, playlist, out)
+		if err == nil {
+			err = out.Close()
+		}
+
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var unsafeFileNameCharacters = regexp.MustCompile(`[^\p{L}\p{N}\-_. ]+`)
+
+// FileName returns a file name which is unique for the playlist and safe to
+// use on every platform
+func FileName(playlist Playlist, format Format) string {
+	name := unsafeFileNameCharacters.ReplaceAllString(playlist.Name, "_")
+	return fmt.Sprintf("%s_%s.%s", name, playlist.Id, format.Extension)
+}
+
+func exportPlaylist(source PlaylistSource, playlist Playlist, out Exporter) error {
+	if err := out.BeginPlaylist(playlist); err != nil {
+		return err
+	}
+
+	err := source.Tracks(playlist, out.WriteTrack)
+	if err != nil {
+		return fmt.Errorf("failed to export playlist %s: %w", playlist.Id, err)
+	}
+
+	return out.EndPlaylist()
+}
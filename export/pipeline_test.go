@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Mock Playlist Source
+type mockSource struct {
+	playlists []Playlist
+	tracks    map[string][]Track
+
+	playlistsError error
+}
+
+func (m mockSource) Playlists() ([]Playlist, error) {
+	return m.playlists, m.playlistsError
+}
+
+func (m mockSource) Tracks(playlist Playlist, handle func(Track) error) error {
+	for _, track := range m.tracks[playlist.Id] {
+		if err := handle(track); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	t.Run("it should export every playlist of the source", func(t *testing.T) {
+		// Given a source with two playlists
+		source := createSource()
+
+		// When exporting it as ndjson
+		buffer := &bytes.Buffer{}
+		err := Export(source, mustFormat(t, "ndjson"), buffer, Options{})
+
+		// Then no error should be returned
+		if err != nil {
+			t.Fatalf("Export returned an error: %s", err.Error())
+		}
+
+		// and a line per track should be written
+		lines := bytes.Count(buffer.Bytes(), []byte("\n"))
+		if lines != 3 {
+			t.Errorf("Expected 3 lines, got %d", lines)
+		}
+	})
+
+	t.Run("it should return an error if the playlists cannot be listed", func(t *testing.T) {
+		// Given a failing source
+		source := mockSource{playlistsError: errors.New("mock error")}
+
+		// When exporting it
+		err := Export(source, mustFormat(t, "json"), &bytes.Buffer{}, Options{})
+
+		// Then an error should be returned
+		expectedError := "failed to list playlists: mock error"
+		if err == nil || err.Error() != expectedError {
+			t.Errorf("Expected '%s', got '%v'", expectedError, err)
+		}
+	})
+}
+
+func TestExportToDirectory(t *testing.T) {
+	t.Run("it should write a file per playlist", func(t *testing.T) {
+		// Given a source with two playlists
+		source := createSource()
+		directory := t.TempDir()
+
+		// When exporting it as m3u8 to a directory
+		err := ExportToDirectory(source, mustFormat(t, "m3u8"), directory, Options{})
+
+		// Then no error should be returned
+		if err != nil {
+			t.Fatalf("ExportToDirectory returned an error: %s", err.Error())
+		}
+
+		// and a file should be written for every playlist
+		for _, playlist := range source.playlists {
+			content, err := os.ReadFile(filepath.Join(directory, FileName(playlist, mustFormat(t, "m3u8"))))
+			if err != nil {
+				t.Fatalf("Expected a file for playlist %s: %s", playlist.Id, err.Error())
+			}
+			if !strings.HasPrefix(string(content), "#EXTM3U\n#PLAYLIST:"+playlist.Name) {
+				t.Errorf("Unexpected content for playlist %s: %s", playlist.Id, content)
+			}
+		}
+	})
+
+	t.Run("it should refuse single playlist formats on a shared writer", func(t *testing.T) {
+		err := Export(createSource(), mustFormat(t, "xspf"), &bytes.Buffer{}, Options{})
+
+		if err == nil {
+			t.Errorf("Expected an error exporting many playlists in a single xspf document")
+		}
+	})
+
+	t.Run("it should export a single playlist in a single playlist format", func(t *testing.T) {
+		source := createSource()
+		source.playlists = source.playlists[1:]
+		buffer := &bytes.Buffer{}
+
+		err := Export(source, mustFormat(t, "m3u8"), buffer, Options{})
+
+		if err != nil || !strings.HasPrefix(buffer.String(), "#EXTM3U\n#PLAYLIST:Second") {
+			t.Errorf("Unexpected output %s (%v)", buffer.String(), err)
+		}
+	})
+}
+
+func TestFileName(t *testing.T) {
+	name := FileName(Playlist{Id: "abc", Name: "Rock/Pop: 80's"}, mustFormat(t, "xspf"))
+
+	if name != "Rock_Pop_ 80_s_abc.xspf" {
+		t.Errorf("Expected a sanitized file name, got %s", name)
+	}
+}
+
+// Helpers
+func mustFormat(t *testing.T, name string) Format {
+	format, err := ParseFormat(name)
+	if err != nil {
+		t.Fatalf("Format %s is not registered", name)
+	}
+
+	return format
+}
+
+func createSource() mockSource {
+	addedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	return mockSource{
+		playlists: []Playlist{
+			{Id: "p1", Name: "First", SnapshotId: "s1", TrackCount: 2},
+			{Id: "p2", Name: "Second", SnapshotId: "s2", TrackCount: 1},
+		},
+		tracks: map[string][]Track{
+			"p1": {
+				{Position: 0, Id: "t1", Name: "Song 1", Artists: []string{"Artist 1"}, DurationMs: 180000, AddedAt: addedAt},
+				{Position: 1, Id: "t2", Name: "Song 2", Artists: []string{"Artist 1", "Artist 2"}, DurationMs: 200000, AddedAt: addedAt},
+			},
+			"p2": {
+				{Position: 0, Id: "t3", Name: "Song 3", Artists: []string{"Artist 3"}, DurationMs: 240000, AddedAt: addedAt},
+			},
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Exporter receives the playlists and their tracks in order, a playlist is
+// always opened by BeginPlaylist and terminated by EndPlaylist
+type Exporter interface {
+	BeginPlaylist(Playlist) error
+	WriteTrack(Track) error
+	EndPlaylist() error
+	Close() error
+}
+
+type Format struct {
+	Name        string
+	Extension   string
+	Description string
+	// SinglePlaylist formats cannot hold more than a playlist per file
+	SinglePlaylist bool
+	New            func(io.Writer, Options) Exporter
+}
+
+var registry = map[string]Format{}
+
+// Register makes a format available to the export pipeline, it is meant to
+// be called from the init function of the file implementing the format
+func Register(format Format) {
+	if _, ok := registry[format.Name]; ok {
+		panic(fmt.Sprintf("export format %s registered twice", format.Name))
+	}
+
+	registry[format.Name] = format
+}
+
+// ParseFormat looks up a registered format by its name or file extension
+func ParseFormat(value string) (Format, error) {
+	value = strings.ToLower(strings.TrimPrefix(value, "."))
+	if format, ok := registry[value]; ok {
+		return format, nil
+	}
+
+	for _, format := range registry {
+		if format.Extension == value {
+			return format, nil
+		}
+	}
+
+	return Format{}, fmt.Errorf("unknown export format: %s", value)
+}
+
+// FormatForFile looks up a registered format by the extension of the path
+func FormatForFile(path string) (Format, error) {
+	return ParseFormat(filepath.Ext(path))
+}
+
+// Formats returns every registered format sorted by name
+func Formats() []Format {
+	formats := make([]Format, 0, len(registry))
+	for _, format := range registry {
+		formats = append(formats, format)
+	}
+
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+
+	return formats
+}
+
+// PrintFormats writes a table of the registered formats
+func PrintFormats(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "NAME\tEXTENSION\tDESCRIPTION")
+	for _, format := range Formats() {
+		fmt.Fprintf(table, "%s\t.%s\t%s\n", format.Name, format.Extension, format.Description)
+	}
+
+	return table.Flush()
+}
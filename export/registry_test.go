@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("it should look up formats by name and extension", func(t *testing.T) {
+		for _, value := range []string{"csv", "json", "ndjson", "m3u8", "xspf", ".json", "XSPF"} {
+			format, err := ParseFormat(value)
+			if err != nil {
+				t.Errorf("Expected format %s to be registered: %s", value, err.Error())
+				continue
+			}
+
+			if format.New(io.Discard, Options{}) == nil {
+				t.Errorf("Expected format %s to create an exporter", value)
+			}
+		}
+	})
+
+	t.Run("it should look up formats by file path", func(t *testing.T) {
+		format, err := FormatForFile("/tmp/playlist.m3u8")
+
+		if err != nil || format.Name != "m3u8" {
+			t.Errorf("Expected m3u8 format, got '%s' (%v)", format.Name, err)
+		}
+	})
+
+	t.Run("it should return an error for unknown formats", func(t *testing.T) {
+		_, err := ParseFormat("yaml")
+
+		if err == nil || err.Error() != "unknown export format: yaml" {
+			t.Errorf("Expected an unknown format error, got %v", err)
+		}
+	})
+
+	t.Run("it should panic when registering a format twice", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic registering json twice")
+			}
+		}()
+
+		Register(Format{Name: "json"})
+	})
+
+	t.Run("it should print the available formats sorted by name", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		PrintFormats(buffer)
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		if len(lines) != len(Formats())+1 {
+			t.Fatalf("Expected a line per format plus the header, got %s", buffer.String())
+		}
+		if !strings.HasPrefix(lines[1], "csv ") || !strings.HasPrefix(lines[len(lines)-1], "xspf ") {
+			t.Errorf("Expected formats sorted by name, got %s", buffer.String())
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package export
+
+import "time"
+
+// SchemaVersion identifies the layout of the exported documents, it is
+// bumped whenever a field is renamed or removed
+const SchemaVersion = 1
+
+type Playlist struct {
+	Id            string `json:"id"`
+	Uri           string `json:"uri"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Owner         string `json:"owner"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+	SnapshotId    string `json:"snapshot_id"`
+	TrackCount    int    `json:"track_count"`
+}
+
+// Types of the playlist items, the type is empty in exports predating them
+const (
+	TypeTrack   = "track"
+	TypeEpisode = "episode"
+	// TypeLocal is a local file, it has no id and cannot be added through the API
+	TypeLocal = "local"
+)
+
+type Track struct {
+	Position    int       `json:"position"`
+	Type        string    `json:"type,omitempty"`
+	Id          string    `json:"id"`
+	Uri         string    `json:"uri"`
+	Name        string    `json:"name"`
+	Artists     []string  `json:"artists"`
+	ArtistIds   []string  `json:"artist_ids"`
+	Album       string    `json:"album"`
+	AlbumId     string    `json:"album_id"`
+	ReleaseDate string    `json:"release_date"`
+	DurationMs  int       `json:"duration_ms"`
+	Isrc        string    `json:"isrc"`
+	Explicit    bool      `json:"explicit"`
+	Popularity  int       `json:"popularity"`
+	AddedAt     time.Time `json:"added_at"`
+	AddedBy     string    `json:"added_by"`
+
+	// Set by the enrichment stages only
+	AudioFeatures *AudioFeatures  `json:"audio_features,omitempty"`
+	ArtistDetails []ArtistDetails `json:"artist_details,omitempty"`
+	AlbumDetails  *AlbumDetails   `json:"album_details,omitempty"`
+}
+
+type AudioFeatures struct {
+	Danceability  float64 `json:"danceability"`
+	Energy        float64 `json:"energy"`
+	Key           int     `json:"key"`
+	Mode          int     `json:"mode"`
+	Tempo         float64 `json:"tempo"`
+	Valence       float64 `json:"valence"`
+	Loudness      float64 `json:"loudness"`
+	TimeSignature int     `json:"time_signature"`
+}
+
+// Document is the layout of a json export:
+//
+//	{"schema_version": 1, "playlists": [{"playlist": {...}, "tracks": [{...}]}]}
+type Document struct {
+	SchemaVersion int             `json:"schema_version"`
+	Playlists     []PlaylistEntry `json:"playlists"`
+}
+
+type PlaylistEntry struct {
+	Playlist Playlist `json:"playlist"`
+	Tracks   []Track  `json:"tracks"`
+}
+
+// Record is a single line of a ndjson export, one per track
+type Record struct {
+	SchemaVersion int    `json:"schema_version"`
+	PlaylistId    string `json:"playlist_id"`
+	PlaylistName  string `json:"playlist_name"`
+	Track         Track  `json:"track"`
+}
+
+type ArtistDetails struct {
+	Id         string   `json:"id"`
+	Genres     []string `json:"genres"`
+	Followers  int      `json:"followers"`
+	Popularity int      `json:"popularity"`
+}
+
+type AlbumDetails struct {
+	Id                   string   `json:"id"`
+	Label                string   `json:"label"`
+	ReleaseDate          string   `json:"release_date"`
+	ReleaseDatePrecision string   `json:"release_date_precision"`
+	Upc                  string   `json:"upc"`
+	Copyrights           []string `json:"copyrights"`
+}
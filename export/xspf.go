@@ -0,0 +1,103 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	xspfNamespace = "http://xspf.org/ns/0/"
+	// XspfIsrcRel is the rel attribute of the meta element carrying the ISRC
+	XspfIsrcRel = "https://isrc.ifpi.org/"
+)
+
+type xspfMeta struct {
+	Rel   string `xml:"rel,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Children are declared in the order required by the XSPF specification
+type xspfTrack struct {
+	XMLName    xml.Name  `xml:"track"`
+	Identifier string    `xml:"identifier,omitempty"`
+	Title      string    `xml:"title,omitempty"`
+	Creator    string    `xml:"creator,omitempty"`
+	Album      string    `xml:"album,omitempty"`
+	Duration   int       `xml:"duration,omitempty"`
+	Meta       *xspfMeta `xml:"meta,omitempty"`
+}
+
+func init() {
+	Register(Format{
+		Name:           "xspf",
+		Extension:      "xspf",
+		Description:    "XML Shareable Playlist Format",
+		SinglePlaylist: true,
+		New:            func(w io.Writer, _ Options) Exporter { return NewXspfWriter(w) },
+	})
+}
+
+// XspfWriter writes a XSPF document, it expects a single playlist per writer
+type XspfWriter struct {
+	writer io.Writer
+}
+
+func NewXspfWriter(writer io.Writer) *XspfWriter {
+	return &XspfWriter{writer: writer}
+}
+
+func (x *XspfWriter) BeginPlaylist(playlist Playlist) error {
+	var header strings.Builder
+	header.WriteString(xml.Header)
+	header.WriteString(fmt.Sprintf(`<playlist version="1" xmlns="%s">`, xspfNamespace))
+	writeXmlElement(&header, "title", playlist.Name)
+	writeXmlElement(&header, "creator", playlist.Owner)
+	writeXmlElement(&header, "annotation", playlist.Description)
+	writeXmlElement(&header, "identifier", playlist.Uri)
+	header.WriteString("<trackList>")
+
+	_, err := io.WriteString(x.writer, header.String())
+	return err
+}
+
+func (x *XspfWriter) WriteTrack(track Track) error {
+	element := xspfTrack{
+		Identifier: track.Uri,
+		Title:      track.Name,
+		Creator:    strings.Join(track.Artists, ", "),
+		Album:      track.Album,
+		Duration:   track.DurationMs,
+	}
+	if track.Isrc != "" {
+		element.Meta = &xspfMeta{Rel: XspfIsrcRel, Value: track.Isrc}
+	}
+
+	encoded, err := xml.Marshal(element)
+	if err != nil {
+		return fmt.Errorf("failed to marshal track %s: %w", track.Id, err)
+	}
+
+	_, err = x.writer.Write(encoded)
+	return err
+}
+
+func (x *XspfWriter) EndPlaylist() error {
+	_, err := io.WriteString(x.writer, "</trackList></playlist>\n")
+	return err
+}
+
+func (x *XspfWriter) Close() error {
+	return nil
+}
+
+func writeXmlElement(builder *strings.Builder, name string, value string) {
+	if value == "" {
+		return
+	}
+
+	builder.WriteString("<" + name + ">")
+	xml.EscapeText(builder, []byte(value))
+	builder.WriteString("</" + name + ">")
+}
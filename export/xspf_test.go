@@ -0,0 +1,161 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// Structure of a XSPF document as per https://xspf.org/spec
+type xspfDocument struct {
+	XMLName    xml.Name `xml:"http://xspf.org/ns/0/ playlist"`
+	Version    string   `xml:"version,attr"`
+	Title      string   `xml:"title"`
+	Creator    string   `xml:"creator"`
+	Annotation string   `xml:"annotation"`
+	Identifier string   `xml:"identifier"`
+	TrackList  struct {
+		Tracks []struct {
+			Identifier string `xml:"identifier"`
+			Title      string `xml:"title"`
+			Creator    string `xml:"creator"`
+			Album      string `xml:"album"`
+			Duration   int    `xml:"duration"`
+			Meta       []struct {
+				Rel   string `xml:"rel,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"meta"`
+		} `xml:"track"`
+	} `xml:"trackList"`
+}
+
+// Order of the children as required by the specification
+var (
+	xspfPlaylistOrder = []string{"title", "creator", "annotation", "info", "location", "identifier", "image", "date", "license", "attribution", "link", "meta", "extension", "trackList"}
+	xspfTrackOrder    = []string{"location", "identifier", "title", "creator", "annotation", "info", "image", "album", "trackNum", "duration", "link", "meta", "extension"}
+)
+
+func TestXspfWriter(t *testing.T) {
+	t.Run("it should write a valid XSPF document", func(t *testing.T) {
+		// Given a xspf writer
+		buffer := &bytes.Buffer{}
+		writer := NewXspfWriter(buffer)
+
+		// When writing a playlist with special characters
+		writer.BeginPlaylist(Playlist{
+			Id:          "p1",
+			Uri:         "spotify:playlist:p1",
+			Name:        "Rock & <Roll>",
+			Owner:       "owner",
+			Description: "A description",
+		})
+		writer.WriteTrack(Track{
+			Uri:        "spotify:track:t1",
+			Name:       "Song",
+			Artists:    []string{"Artist 1", "Artist 2"},
+			Album:      "Album",
+			DurationMs: 180000,
+			Isrc:       "USRC17607839",
+		})
+		writer.WriteTrack(Track{Uri: "spotify:track:t2", Name: "No ISRC"})
+		writer.EndPlaylist()
+		writer.Close()
+
+		// Then the document should be decodable
+		var document xspfDocument
+		if err := xml.Unmarshal(buffer.Bytes(), &document); err != nil {
+			t.Fatalf("Expected valid xml, got %s: %s", err.Error(), buffer.String())
+		}
+
+		// and have the expected playlist metadata
+		if document.Version != "1" {
+			t.Errorf("Expected version 1, got %s", document.Version)
+		}
+		if document.Title != "Rock & <Roll>" || document.Creator != "owner" || document.Identifier != "spotify:playlist:p1" {
+			t.Errorf("Unexpected playlist metadata: %+v", document)
+		}
+
+		// and tracks
+		tracks := document.TrackList.Tracks
+		if len(tracks) != 2 {
+			t.Fatalf("Expected 2 tracks, got %d", len(tracks))
+		}
+		first := tracks[0]
+		if first.Identifier != "spotify:track:t1" || first.Title != "Song" || first.Creator != "Artist 1, Artist 2" ||
+			first.Album != "Album" || first.Duration != 180000 {
+			t.Errorf("Unexpected track: %+v", first)
+		}
+		if len(first.Meta) != 1 || first.Meta[0].Rel != XspfIsrcRel || first.Meta[0].Value != "USRC17607839" {
+			t.Errorf("Expected ISRC meta, got %+v", first.Meta)
+		}
+		if len(tracks[1].Meta) != 0 {
+			t.Errorf("Expected no meta without ISRC, got %+v", tracks[1].Meta)
+		}
+	})
+
+	t.Run("it should respect the element order of the specification", func(t *testing.T) {
+		// Given a written document
+		buffer := &bytes.Buffer{}
+		writer := NewXspfWriter(buffer)
+		writer.BeginPlaylist(Playlist{Uri: "spotify:playlist:p1", Name: "name", Owner: "owner", Description: "description"})
+		writer.WriteTrack(Track{Uri: "spotify:track:t1", Name: "Song", Artists: []string{"Artist"}, Album: "Album", DurationMs: 1, Isrc: "isrc"})
+		writer.EndPlaylist()
+
+		// When walking its elements
+		decoder := xml.NewDecoder(buffer)
+		var path []string
+		lastIndex := map[string]int{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				break
+			}
+
+			switch element := token.(type) {
+			case xml.StartElement:
+				if element.Name.Space != xspfNamespace {
+					t.Errorf("Element %s is not in the XSPF namespace", element.Name.Local)
+				}
+
+				// Then every child should come after its preceding siblings
+				parent := strings.Join(path, "/")
+				var order []string
+				switch parent {
+				case "playlist":
+					order = xspfPlaylistOrder
+				case "playlist/trackList/track":
+					order = xspfTrackOrder
+				}
+				if order != nil {
+					index := indexOf(order, element.Name.Local)
+					if index < lastIndex[parent] {
+						t.Errorf("Element %s is out of order in %s", element.Name.Local, parent)
+					}
+					lastIndex[parent] = index
+				}
+
+				path = append(path, element.Name.Local)
+			case xml.EndElement:
+				if path[len(path)-1] == "track" {
+					delete(lastIndex, "playlist/trackList/track")
+				}
+				path = path[:len(path)-1]
+			}
+		}
+
+		if len(path) != 0 {
+			t.Errorf("Expected every element to be closed, open: %v", path)
+		}
+	})
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
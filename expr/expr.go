@@ -0,0 +1,318 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Expression is a parsed expression over the fields of a track, such as
+// `year >= 2000 && !explicit` or `artist + " " + name`.
+//
+// Values are numbers, strings or booleans. Supported operators by
+// increasing precedence are ||, &&, the comparisons == != < <= > >= and the
+// case insensitive regular expression match ~, + - (+ concatenates strings),
+// * / % and the unary ! and -.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Env resolves the identifiers of the expression
+type Env map[string]any
+
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %s at %d", describe(p.peek()), p.peek().position)
+	}
+
+	return &Expression{source, root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Identifiers returns the sorted names referenced by the expression
+func (e *Expression) Identifiers() []string {
+	names := map[string]bool{}
+	e.root.identifiers(names)
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Eval evaluates the expression, unknown identifiers are an error while
+// identifiers set to nil evaluate to nil and make any operation nil
+func (e *Expression) Eval(env Env) (any, error) {
+	return e.root.eval(env)
+}
+
+// EvalBool evaluates the expression as a condition, nil is false
+func (e *Expression) EvalBool(env Env) (bool, error) {
+	value, err := e.Eval(env)
+	if err != nil || value == nil {
+		return false, err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q is not a condition", e.source)
+	}
+
+	return result, nil
+}
+
+type node interface {
+	eval(env Env) (any, error)
+	identifiers(names map[string]bool)
+}
+
+type literal struct {
+	value any
+}
+
+func (l literal) eval(Env) (any, error)       { return l.value, nil }
+func (l literal) identifiers(map[string]bool) {}
+
+type identifier struct {
+	name string
+}
+
+func (i identifier) eval(env Env) (any, error) {
+	value, ok := env[i.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s", i.name)
+	}
+
+	// Normalize the numeric types of the environment
+	switch number := value.(type) {
+	case int:
+		return float64(number), nil
+	case int64:
+		return float64(number), nil
+	}
+
+	return value, nil
+}
+
+func (i identifier) identifiers(names map[string]bool) {
+	names[i.name] = true
+}
+
+type unary struct {
+	operator string
+	operand  node
+}
+
+func (u unary) eval(env Env) (any, error) {
+	value, err := u.operand.eval(env)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	switch u.operator {
+	case "!":
+		if b, ok := value.(bool); ok {
+			return !b, nil
+		}
+	case "-":
+		if n, ok := value.(float64); ok {
+			return -n, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid operand for %s: %v", u.operator, value)
+}
+
+func (u unary) identifiers(names map[string]bool) {
+	u.operand.identifiers(names)
+}
+
+type binary struct {
+	operator    string
+	left, right node
+}
+
+func (b binary) identifiers(names map[string]bool) {
+	b.left.identifiers(names)
+	b.right.identifiers(names)
+}
+
+func (b binary) eval(env Env) (any, error) {
+	left, err := b.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logical operators short circuit, nil is false
+	if b.operator == "&&" || b.operator == "||" {
+		condition, err := asCondition(b.operator, left)
+		if err != nil {
+			return nil, err
+		}
+		if b.operator == "&&" && !condition {
+			return false, nil
+		}
+		if b.operator == "||" && condition {
+			return true, nil
+		}
+
+		right, err := b.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return asCondition(b.operator, right)
+	}
+
+	right, err := b.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Equality is the only operation defined on nil
+	switch b.operator {
+	case "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	}
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	switch b.operator {
+	case "~":
+		pattern, err := regexp.Compile("(?i)" + fmt.Sprint(right))
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", right, err)
+		}
+		return pattern.MatchString(fmt.Sprint(left)), nil
+	case "<", "<=", ">", ">=":
+		comparison, err := Compare(left, right)
+		if err != nil {
+			return nil, err
+		}
+		switch b.operator {
+		case "<":
+			return comparison < 0, nil
+		case "<=":
+			return comparison <= 0, nil
+		case ">":
+			return comparison > 0, nil
+		default:
+			return comparison >= 0, nil
+		}
+	}
+
+	if b.operator == "+" {
+		leftString, leftIsString := left.(string)
+		rightString, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			if !leftIsString {
+				leftString = format(left)
+			}
+			if !rightIsString {
+				rightString = format(right)
+			}
+			return leftString + rightString, nil
+		}
+	}
+
+	leftNumber, leftOk := left.(float64)
+	rightNumber, rightOk := right.(float64)
+	if !leftOk || !rightOk {
+		return nil, fmt.Errorf("invalid operands for %s: %v and %v", b.operator, left, right)
+	}
+
+	switch b.operator {
+	case "+":
+		return leftNumber + rightNumber, nil
+	case "-":
+		return leftNumber - rightNumber, nil
+	case "*":
+		return leftNumber * rightNumber, nil
+	case "/":
+		if rightNumber == 0 {
+			return nil, nil
+		}
+		return leftNumber / rightNumber, nil
+	default:
+		if rightNumber == 0 {
+			return nil, nil
+		}
+		return math.Mod(leftNumber, rightNumber), nil
+	}
+}
+
+func asCondition(operator string, value any) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+
+	condition, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid operand for %s: %v", operator, value)
+	}
+
+	return condition, nil
+}
+
+// Compare orders two numbers, strings or booleans, strings are compared
+// case insensitively
+func Compare(left any, right any) (int, error) {
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			switch {
+			case l < r:
+				return -1, nil
+			case l > r:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(strings.ToLower(l), strings.ToLower(r)), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			switch {
+			case l == r:
+				return 0, nil
+			case !l:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot compare %v and %v", left, right)
+}
+
+func format(value any) string {
+	if number, ok := value.(float64); ok {
+		return fmt.Sprintf("%g", number)
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,130 @@
+package expr
+
+import (
+	"reflect"
+	"testing"
+)
+
+var env = Env{
+	"name":     "Under Pressure",
+	"artist":   "Queen",
+	"year":     1981,
+	"explicit": false,
+	"tempo":    113.5,
+	"genre":    nil,
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected any
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"(1 + 2) * 3", 9.0},
+		{"-year", -1981.0},
+		{"10 % 4", 2.0},
+		{"1 / 0", nil},
+		{"year >= 1980 && !explicit", true},
+		{"year < 1980 || explicit", false},
+		{"year >= 2000 && !explicit", false},
+		{"artist == 'Queen'", true},
+		{`artist != "Queen"`, false},
+		{"artist < 'radiohead'", true},
+		{"name ~ 'pressure$'", true},
+		{"name ~ '^live'", false},
+		{"artist + ' - ' + name", "Queen - Under Pressure"},
+		{"name + ' ' + year", "Under Pressure 1981"},
+		{"tempo > 100", true},
+		{"genre == null", true},
+		{"genre != null", false},
+		{"genre > 'rock'", nil},
+		{"genre + 1", nil},
+		{"false && unknown", false},
+		{"true || unknown", true},
+		{"genre || year > 1980", true},
+	}
+
+	for _, test := range tests {
+		expression, err := Parse(test.source)
+		if err != nil {
+			t.Errorf("Parse(%q) returned an error: %s", test.source, err.Error())
+			continue
+		}
+
+		value, err := expression.Eval(env)
+		if err != nil {
+			t.Errorf("Eval(%q) returned an error: %s", test.source, err.Error())
+			continue
+		}
+
+		if value != test.expected {
+			t.Errorf("Expected %q to evaluate to %v, got %v", test.source, test.expected, value)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []string{
+		"unknown > 1",
+		"artist * 2",
+		"year > 'text'",
+		"!year",
+		"name ~ '('",
+		"explicit || name",
+	}
+
+	for _, source := range tests {
+		expression, err := Parse(source)
+		if err != nil {
+			t.Errorf("Parse(%q) returned an error: %s", source, err.Error())
+			continue
+		}
+
+		if _, err := expression.Eval(env); err == nil {
+			t.Errorf("Expected %q to fail", source)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"year >":        "unexpected end of expression at 6",
+		"(year > 1":     "expected ) at 9",
+		"year 1":        "unexpected number 1 at 5",
+		"'unterminated": "unterminated string at 0",
+		"year # 1":      "unexpected character at 5: #",
+	}
+
+	for source, expected := range tests {
+		_, err := Parse(source)
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected %q to fail with '%s', got %v", source, expected, err)
+		}
+	}
+}
+
+func TestEvalBool(t *testing.T) {
+	expression, _ := Parse("genre == 'rock'")
+	if result, err := expression.EvalBool(env); result || err != nil {
+		t.Errorf("Expected false, got %v (%v)", result, err)
+	}
+
+	expression, _ = Parse("genre > 'rock'")
+	if result, err := expression.EvalBool(env); result || err != nil {
+		t.Errorf("Expected nil to be false, got %v (%v)", result, err)
+	}
+
+	expression, _ = Parse("year")
+	if _, err := expression.EvalBool(env); err == nil {
+		t.Errorf("Expected an error for a non boolean expression")
+	}
+}
+
+func TestIdentifiers(t *testing.T) {
+	expression, _ := Parse("tempo > 120 && (artist ~ 'a' || tempo < 10) && true")
+
+	expected := []string{"artist", "tempo"}
+	if identifiers := expression.Identifiers(); !reflect.DeepEqual(identifiers, expected) {
+		t.Errorf("Expected %v, got %v", expected, identifiers)
+	}
+}
@@ -0,0 +1,86 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdentifier
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	number   float64
+	position int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "~", "+", "-", "*", "/", "%", "(", ")"}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			number, err := strconv.ParseFloat(string(runes[start:i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number at %d: %s", start, string(runes[start:i]))
+			}
+			tokens = append(tokens, token{kind: tokenNumber, number: number, position: start})
+		case r == '"' || r == '\'':
+			start := i
+			var value strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:i]), position: start})
+		default:
+			operator := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character at %d: %c", i, r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: i})
+			i += len([]rune(operator))
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, position: len(runes)}), nil
+}
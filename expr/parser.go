@@ -0,0 +1,136 @@
+package expr
+
+import "fmt"
+
+type parser struct {
+	tokens  []token
+	current int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.current]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.current]
+	if t.kind != tokenEnd {
+		p.current++
+	}
+	return t
+}
+
+func (p *parser) accept(operators ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+
+	for _, operator := range operators {
+		if t.text == operator {
+			p.current++
+			return operator, true
+		}
+	}
+
+	return "", false
+}
+
+// parseBinary parses a left associative chain of the given operators
+func (p *parser) parseBinary(operand func() (node, error), operators ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator, ok := p.accept(operators...)
+		if !ok {
+			return left, nil
+		}
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{operator, left, right}
+	}
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *parser) parseComparison() (node, error) {
+	return p.parseBinary(p.parseAdditive, "==", "!=", "<=", ">=", "<", ">", "~")
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if operator, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{operator, operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		return literal{t.number}, nil
+	case tokenString:
+		return literal{t.text}, nil
+	case tokenIdentifier:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		return identifier{t.text}, nil
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("expected ) at %d", p.peek().position)
+			}
+			return inner, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %s at %d", describe(t), t.position)
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokenEnd:
+		return "end of expression"
+	case tokenNumber:
+		return fmt.Sprintf("number %g", t.number)
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+
+	return t.text
+}
@@ -0,0 +1,55 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+// CreatePlaylist creates a playlist with the matched tracks, in the order of
+// the imported file
+func CreatePlaylist(client restore.Client, name string, report *Report, options restore.Options) (*restore.Result, error) {
+	entry := export.PlaylistEntry{Playlist: export.Playlist{Name: name}}
+	for i, match := range report.Matches {
+		entry.Tracks = append(entry.Tracks, export.Track{Position: i, Uri: match.Track.Uri})
+	}
+
+	return restore.Restore(client, entry, options)
+}
+
+// PrintReport writes a line per entry with the confidence of its match
+func PrintReport(writer io.Writer, report *Report) error {
+	var out strings.Builder
+	for _, match := range report.Matches {
+		fmt.Fprintf(
+			&out, "line %d: %s -> %s (%s, %.0f%%)\n",
+			match.Entry.Line, match.Entry, describe(match), match.Method, match.Confidence*100,
+		)
+		if len(match.Reasons) > 0 {
+			fmt.Fprintf(&out, "    %s\n", strings.Join(match.Reasons, ", "))
+		}
+	}
+	for _, entry := range report.Unmatched {
+		fmt.Fprintf(&out, "line %d: %s -> not found\n", entry.Line, entry)
+	}
+	fmt.Fprintf(&out, "%d matched, %d unmatched\n", len(report.Matches), len(report.Unmatched))
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+func describe(match Match) string {
+	if match.Track.Name == "" {
+		return match.Track.Uri
+	}
+
+	artists := make([]string, 0, len(match.Track.Artists))
+	for _, artist := range match.Track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return strings.Join(artists, ", ") + " - " + match.Track.Name
+}
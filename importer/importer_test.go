@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+// Mock Client recording the added uris
+type mockClient struct {
+	name string
+	uris []string
+}
+
+func (m *mockClient) CurrentUser() (*api.User, error) {
+	return &api.User{Id: "user"}, nil
+}
+
+func (m *mockClient) CreatePlaylist(userId string, details api.PlaylistDetails) (*api.SimplifiedPlaylist, error) {
+	m.name = details.Name
+	return &api.SimplifiedPlaylist{Id: "new"}, nil
+}
+
+func (m *mockClient) AddItems(playlistId string, uris []string) (string, error) {
+	m.uris = append(m.uris, uris...)
+	return "snapshot", nil
+}
+
+func TestCreatePlaylist(t *testing.T) {
+	t.Run("it should create a playlist with the matches in file order", func(t *testing.T) {
+		// Given a report with two matches
+		report := &Report{Matches: []Match{
+			{Track: api.Track{Uri: "spotify:track:b"}},
+			{Track: api.Track{Uri: "spotify:track:a"}},
+		}}
+		client := &mockClient{}
+
+		// When creating the playlist
+		result, err := CreatePlaylist(client, "Imported", report, restore.Options{})
+
+		// Then the tracks should be added in order
+		if err != nil || result.Added != 2 {
+			t.Fatalf("Unexpected result %+v (%v)", result, err)
+		}
+		if client.name != "Imported" || client.uris[0] != "spotify:track:b" || client.uris[1] != "spotify:track:a" {
+			t.Errorf("Unexpected playlist %s %v", client.name, client.uris)
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Entry is a line of a local playlist file
+type Entry struct {
+	// Line is the line of the file, or the index of the track for XSPF
+	Line       int
+	Title      string
+	Artists    []string
+	Album      string
+	DurationMs int
+	Isrc       string
+	// Uri is set when the entry already references a spotify track
+	Uri string
+}
+
+// String describes the entry by its artists and title, or by its URI or
+// ISRC when it has no title
+func (e Entry) String() string {
+	switch {
+	case e.Title == "" && e.Uri != "":
+		return e.Uri
+	case e.Title == "":
+		return e.Isrc
+	case len(e.Artists) == 0:
+		return e.Title
+	}
+
+	return strings.Join(e.Artists, ", ") + " - " + e.Title
+}
+
+// ParseFile parses a M3U, XSPF or CSV file depending on its extension and
+// returns the playlist name along with its entries
+func ParseFile(path string) (string, []Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".m3u", ".m3u8":
+		var title string
+		title, entries, err = ParseM3u(file)
+		if title != "" {
+			name = title
+		}
+	case ".xspf":
+		var title string
+		title, entries, err = ParseXspf(file)
+		if title != "" {
+			name = title
+		}
+	case ".csv":
+		entries, err = ParseCsv(file)
+	default:
+		return "", nil, fmt.Errorf("unsupported playlist file: %s", path)
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return name, entries, nil
+}
+
+func ParseM3u(reader io.Reader) (string, []Entry, error) {
+	var name string
+	var entries []Entry
+	var pending *Entry
+
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+
+		switch {
+		case text == "" || text == "#EXTM3U":
+		case strings.HasPrefix(text, "#PLAYLIST:"):
+			name = strings.TrimPrefix(text, "#PLAYLIST:")
+		case strings.HasPrefix(text, "#EXTINF:"):
+			entry := parseExtinf(strings.TrimPrefix(text, "#EXTINF:"))
+			entry.Line = line
+			pending = &entry
+		case strings.HasPrefix(text, "#"):
+		default:
+			// A location terminates the entry
+			entry := Entry{Line: line}
+			if pending != nil {
+				entry = *pending
+			}
+			pending = nil
+
+			if strings.HasPrefix(text, "spotify:track:") {
+				entry.Uri = text
+			} else if entry.Title == "" {
+				entry.Artists, entry.Title = splitArtistTitle(strings.TrimSuffix(filepath.Base(text), filepath.Ext(text)))
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return name, entries, scanner.Err()
+}
+
+// parseExtinf parses "<seconds>,<Artist> - <Title>", attributes between the
+// duration and the comma are ignored
+func parseExtinf(value string) Entry {
+	info, title, _ := strings.Cut(value, ",")
+	seconds, _ := strconv.Atoi(strings.Fields(info + " ")[0])
+
+	entry := Entry{}
+	if seconds > 0 {
+		entry.DurationMs = seconds * 1000
+	}
+	entry.Artists, entry.Title = splitArtistTitle(title)
+
+	return entry
+}
+
+func splitArtistTitle(value string) ([]string, string) {
+	artists, title, found := strings.Cut(value, " - ")
+	if !found {
+		return nil, strings.TrimSpace(value)
+	}
+
+	return splitArtists(artists), strings.TrimSpace(title)
+}
+
+func splitArtists(value string) []string {
+	var artists []string
+	for _, artist := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if artist = strings.TrimSpace(artist); artist != "" {
+			artists = append(artists, artist)
+		}
+	}
+
+	return artists
+}
+
+type xspfPlaylist struct {
+	Title  string `xml:"title"`
+	Tracks []struct {
+		Location   []string `xml:"location"`
+		Identifier []string `xml:"identifier"`
+		Title      string   `xml:"title"`
+		Creator    string   `xml:"creator"`
+		Album      string   `xml:"album"`
+		Duration   int      `xml:"duration"`
+		Meta       []struct {
+			Rel   string `xml:"rel,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"meta"`
+	} `xml:"trackList>track"`
+}
+
+func ParseXspf(reader io.Reader) (string, []Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.NewDecoder(reader).Decode(&playlist); err != nil {
+		return "", nil, err
+	}
+
+	entries := make([]Entry, 0, len(playlist.Tracks))
+	for i, track := range playlist.Tracks {
+		entry := Entry{
+			Line:       i + 1,
+			Title:      track.Title,
+			Artists:    splitArtists(track.Creator),
+			Album:      track.Album,
+			DurationMs: track.Duration,
+		}
+
+		for _, identifier := range append(track.Identifier, track.Location...) {
+			if strings.HasPrefix(identifier, "spotify:track:") {
+				entry.Uri = identifier
+			}
+		}
+		for _, meta := range track.Meta {
+			if meta.Rel == export.XspfIsrcRel {
+				entry.Isrc = strings.TrimSpace(meta.Value)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return playlist.Title, entries, nil
+}
+
+// ParseCsv reads a csv with a header, columns are recognized by name so that
+// exports of other services can be imported as well
+func ParseCsv(reader io.Reader) ([]Entry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	var entries []Entry
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry := Entry{
+			Line:    line,
+			Title:   column(record, "name", "title", "track", "track name"),
+			Artists: splitArtists(column(record, "artists", "artist", "artist name(s)", "artist name")),
+			Album:   column(record, "album", "album name"),
+			Isrc:    column(record, "isrc"),
+		}
+		entry.DurationMs, _ = strconv.Atoi(column(record, "duration_ms", "duration (ms)", "track duration (ms)"))
+
+		uri := column(record, "uri", "track uri", "spotify uri")
+		if strings.HasPrefix(uri, "spotify:track:") {
+			entry.Uri = uri
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestParseM3u(t *testing.T) {
+	t.Run("it should parse extended and plain entries", func(t *testing.T) {
+		// Given a m3u file mixing extended entries, spotify uris and plain paths
+		content := "#EXTM3U\n" +
+			"#PLAYLIST:Road trip\n" +
+			"#EXTINF:215 tvg-id=\"x\",Queen, David Bowie - Under Pressure\n" +
+			"/music/under-pressure.mp3\n" +
+			"#EXTINF:-1,Unknown\n" +
+			"spotify:track:abc\n" +
+			"\n" +
+			"/music/Daft Punk - One More Time.flac\n"
+
+		// When parsing it
+		name, entries, err := ParseM3u(strings.NewReader(content))
+
+		// Then the name and entries should be returned
+		if err != nil {
+			t.Fatalf("ParseM3u returned an error: %s", err.Error())
+		}
+		if name != "Road trip" || len(entries) != 3 {
+			t.Fatalf("Unexpected result %s %+v", name, entries)
+		}
+
+		first := entries[0]
+		if first.Line != 3 || first.Title != "Under Pressure" || len(first.Artists) != 2 || first.Artists[1] != "David Bowie" || first.DurationMs != 215000 {
+			t.Errorf("Unexpected first entry %+v", first)
+		}
+		if entries[1].Uri != "spotify:track:abc" || entries[1].DurationMs != 0 {
+			t.Errorf("Unexpected second entry %+v", entries[1])
+		}
+		if entries[2].Line != 8 || entries[2].Title != "One More Time" || entries[2].Artists[0] != "Daft Punk" {
+			t.Errorf("Expected the entry to be parsed from the file name, got %+v", entries[2])
+		}
+	})
+}
+
+func TestParseXspf(t *testing.T) {
+	t.Run("it should read back an exported XSPF", func(t *testing.T) {
+		// Given a XSPF export
+		buffer := &bytes.Buffer{}
+		writer := export.NewXspfWriter(buffer)
+		writer.BeginPlaylist(export.Playlist{Name: "Exported"})
+		writer.WriteTrack(export.Track{Uri: "spotify:track:t1", Name: "Song", Artists: []string{"A", "B"}, Isrc: "ISRC1", DurationMs: 1000})
+		writer.WriteTrack(export.Track{Name: "Local", Artists: []string{"C"}, Album: "Album"})
+		writer.EndPlaylist()
+
+		// When parsing it
+		name, entries, err := ParseXspf(buffer)
+
+		// Then the entries should be returned
+		if err != nil {
+			t.Fatalf("ParseXspf returned an error: %s", err.Error())
+		}
+		if name != "Exported" || len(entries) != 2 {
+			t.Fatalf("Unexpected result %s %+v", name, entries)
+		}
+		if entries[0].Uri != "spotify:track:t1" || entries[0].Isrc != "ISRC1" || len(entries[0].Artists) != 2 {
+			t.Errorf("Unexpected first entry %+v", entries[0])
+		}
+		if entries[1].Line != 2 || entries[1].Uri != "" || entries[1].Album != "Album" {
+			t.Errorf("Unexpected second entry %+v", entries[1])
+		}
+	})
+}
+
+func TestParseCsv(t *testing.T) {
+	t.Run("it should recognize the columns by name", func(t *testing.T) {
+		content := "Track Name,Artist Name(s),ISRC,Duration (ms)\n" +
+			"Song,\"A, B\",ISRC1,180000\n" +
+			"Other,C,,\n"
+
+		entries, err := ParseCsv(strings.NewReader(content))
+
+		if err != nil {
+			t.Fatalf("ParseCsv returned an error: %s", err.Error())
+		}
+		if len(entries) != 2 || entries[0].Title != "Song" || entries[0].Artists[1] != "B" || entries[0].Isrc != "ISRC1" || entries[0].DurationMs != 180000 {
+			t.Errorf("Unexpected entries %+v", entries)
+		}
+		if entries[1].Line != 3 || entries[1].Artists[0] != "C" {
+			t.Errorf("Unexpected second entry %+v", entries[1])
+		}
+	})
+}
+
+func TestParseFile(t *testing.T) {
+	t.Run("it should pick the parser from the extension and default the name to the file name", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "Legacy.csv")
+		os.WriteFile(path, []byte("title,artist\nSong,Artist\n"), 0o644)
+
+		name, entries, err := ParseFile(path)
+
+		if err != nil || name != "Legacy" || len(entries) != 1 {
+			t.Errorf("Unexpected result %s %+v (%v)", name, entries, err)
+		}
+	})
+
+	t.Run("it should refuse unknown extensions", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "playlist.pls")
+		os.WriteFile(path, []byte(""), 0o644)
+
+		if _, _, err := ParseFile(path); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/matcher"
+)
+
+const (
+	MethodUri    = "uri"
+	MethodIsrc   = "isrc"
+	MethodSearch = "search"
+
+	searchLimit = 10
+)
+
+// DefaultMinConfidence is the confidence below which a match is rejected
+const DefaultMinConfidence = 0.6
+
+type Searcher interface {
+	SearchTracks(query string, limit int) ([]api.Track, error)
+}
+
+type Match struct {
+	Entry      Entry
+	Track      api.Track
+	Confidence float64
+	Method     string
+	// Reasons explains the confidence of search matches
+	Reasons []string
+}
+
+type Report struct {
+	Matches   []Match
+	Unmatched []Entry
+}
+
+// Resolve looks up every entry on spotify, by ISRC first and then by
+// searching its artist and title. Matches below minConfidence are reported
+// as unmatched.
+func Resolve(searcher Searcher, entries []Entry, minConfidence float64) (*Report, error) {
+	report := &Report{}
+	for _, entry := range entries {
+		match, err := resolve(searcher, entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve line %d: %w", entry.Line, err)
+		}
+
+		if match == nil || match.Confidence < minConfidence {
+			report.Unmatched = append(report.Unmatched, entry)
+			continue
+		}
+
+		report.Matches = append(report.Matches, *match)
+	}
+
+	return report, nil
+}
+
+func resolve(searcher Searcher, entry Entry) (*Match, error) {
+	if entry.Uri != "" {
+		return &Match{Entry: entry, Track: api.Track{Uri: entry.Uri}, Confidence: 1, Method: MethodUri}, nil
+	}
+
+	if entry.Isrc != "" {
+		tracks, err := searcher.SearchTracks("isrc:"+entry.Isrc, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(tracks) > 0 {
+			return &Match{Entry: entry, Track: tracks[0], Confidence: 1, Method: MethodIsrc}, nil
+		}
+	}
+
+	if entry.Title == "" {
+		return nil, nil
+	}
+
+	query := "track:" + api.QuoteSearch(entry.Title)
+	if len(entry.Artists) > 0 {
+		query += " artist:" + api.QuoteSearch(entry.Artists[0])
+	}
+	tracks, err := searcher.SearchTracks(query, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	candidates := make([]matcher.Track, 0, len(tracks))
+	for _, track := range tracks {
+		candidates = append(candidates, toMatcherTrack(track))
+	}
+
+	wanted := matcher.Track{Title: entry.Title, Artists: entry.Artists, DurationMs: entry.DurationMs, Isrc: entry.Isrc}
+	best := matcher.NewMatcher().Rank(wanted, candidates)[0]
+
+	return &Match{
+		Entry:      entry,
+		Track:      tracks[best.Index],
+		Confidence: best.Score,
+		Method:     MethodSearch,
+		Reasons:    best.Reasons,
+	}, nil
+}
+
+func toMatcherTrack(track api.Track) matcher.Track {
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return matcher.Track{
+		Id:         track.Id,
+		Title:      track.Name,
+		Artists:    artists,
+		DurationMs: track.DurationMs,
+		Isrc:       track.ExternalIds.Isrc,
+	}
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"bytes"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+)
+
+// Mock Searcher answering from a map of queries
+type mockSearcher struct {
+	results map[string][]api.Track
+	queries []string
+}
+
+func (m *mockSearcher) SearchTracks(query string, limit int) ([]api.Track, error) {
+	m.queries = append(m.queries, query)
+	return m.results[query], nil
+}
+
+func TestResolve(t *testing.T) {
+	underPressure := track("t1", "Under Pressure", 248000, "Queen", "David Bowie")
+	karaoke := track("t2", "Under Pressure - Karaoke Version", 250000, "Karaoke Band")
+
+	searcher := &mockSearcher{results: map[string][]api.Track{
+		"isrc:GBUM71029604":                           {underPressure},
+		`track:"Under Pressure" artist:"Queen"`:       {karaoke, underPressure},
+		`track:"Unknown Song" artist:"Nobody"`:        {karaoke},
+		`track:"Under Pressure" artist:"David Bowie"`: {underPressure},
+		`track:"Under Pressure Live" artist:"Queen"`:  {underPressure},
+	}}
+
+	t.Run("it should resolve by uri, then isrc, then search", func(t *testing.T) {
+		// Given entries resolvable in different ways
+		entries := []Entry{
+			{Line: 1, Uri: "spotify:track:known"},
+			{Line: 2, Isrc: "GBUM71029604", Title: "ignored"},
+			{Line: 3, Title: "Under Pressure", Artists: []string{"Queen"}, DurationMs: 248000},
+			{Line: 4, Title: "Unknown Song", Artists: []string{"Nobody"}},
+		}
+
+		// When resolving them
+		report, err := Resolve(searcher, entries, 0.6)
+
+		// Then the first three should be matched
+		if err != nil {
+			t.Fatalf("Resolve returned an error: %s", err.Error())
+		}
+		if len(report.Matches) != 3 {
+			t.Fatalf("Expected 3 matches, got %+v", report.Matches)
+		}
+
+		expectedMethods := []string{MethodUri, MethodIsrc, MethodSearch}
+		for i, match := range report.Matches {
+			if match.Method != expectedMethods[i] {
+				t.Errorf("Expected line %d to be resolved by %s, got %s", match.Entry.Line, expectedMethods[i], match.Method)
+			}
+		}
+
+		// and the search should pick the closest track
+		if report.Matches[2].Track.Id != "t1" || report.Matches[2].Confidence < 0.9 {
+			t.Errorf("Expected the original track to be picked, got %+v", report.Matches[2])
+		}
+
+		// and the unknown song should be unmatched
+		if len(report.Unmatched) != 1 || report.Unmatched[0].Line != 4 {
+			t.Errorf("Unexpected unmatched entries %+v", report.Unmatched)
+		}
+	})
+
+	t.Run("it should fall back to search when the isrc is unknown", func(t *testing.T) {
+		entries := []Entry{{Line: 1, Isrc: "UNKNOWN", Title: "Under Pressure", Artists: []string{"David Bowie"}}}
+
+		report, _ := Resolve(searcher, entries, 0.5)
+
+		if len(report.Matches) != 1 || report.Matches[0].Method != MethodSearch {
+			t.Errorf("Expected a search match, got %+v", report)
+		}
+	})
+
+	t.Run("it should replace the quotes of the title in the query", func(t *testing.T) {
+		entries := []Entry{{Line: 1, Title: `Under Pressure "Live"`, Artists: []string{"Queen"}}}
+
+		Resolve(searcher, entries, 0.5)
+
+		if last := searcher.queries[len(searcher.queries)-1]; last != `track:"Under Pressure Live" artist:"Queen"` {
+			t.Errorf("Unexpected query %s", last)
+		}
+	})
+
+	t.Run("it should print the confidence of every line", func(t *testing.T) {
+		report, _ := Resolve(searcher, []Entry{
+			{Line: 2, Isrc: "GBUM71029604"},
+			{Line: 3, Title: "Unknown Song", Artists: []string{"Nobody"}},
+		}, 0.6)
+
+		buffer := &bytes.Buffer{}
+		PrintReport(buffer, report)
+
+		expected := "line 2: GBUM71029604 -> Queen, David Bowie - Under Pressure (isrc, 100%)\n" +
+			"line 3: Nobody - Unknown Song -> not found\n" +
+			"1 matched, 1 unmatched\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+}
+
+// Helpers
+func track(id string, name string, durationMs int, artists ...string) api.Track {
+	track := api.Track{Id: id, Uri: "spotify:track:" + id, Name: name, DurationMs: durationMs}
+	for _, artist := range artists {
+		track.Artists = append(track.Artists, api.SimplifiedArtist{Name: artist})
+	}
+
+	return track
+}
@@ -0,0 +1,88 @@
+package library
+
+import (
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+func ToPlaylist(playlist api.SimplifiedPlaylist) export.Playlist {
+	return export.Playlist{
+		Id:            playlist.Id,
+		Uri:           playlist.Uri,
+		Name:          playlist.Name,
+		Description:   playlist.Description,
+		Owner:         playlist.Owner.Id,
+		Public:        playlist.Public,
+		Collaborative: playlist.Collaborative,
+		SnapshotId:    playlist.SnapshotId,
+		TrackCount:    playlist.Tracks.Total,
+	}
+}
+
+func ToTrack(position int, track api.Track) export.Track {
+	artists := make([]string, 0, len(track.Artists))
+	artistIds := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+		artistIds = append(artistIds, artist.Id)
+	}
+
+	return export.Track{
+		Position:    position,
+		Type:        export.TypeTrack,
+		Id:          track.Id,
+		Uri:         track.Uri,
+		Name:        track.Name,
+		Artists:     artists,
+		ArtistIds:   artistIds,
+		Album:       track.Album.Name,
+		AlbumId:     track.Album.Id,
+		ReleaseDate: track.Album.ReleaseDate,
+		DurationMs:  track.DurationMs,
+		Isrc:        track.ExternalIds.Isrc,
+		Explicit:    track.Explicit,
+		Popularity:  track.Popularity,
+	}
+}
+
+// ToEpisodeTrack exports an episode as a track of its show album, the
+// publisher being the artist
+func ToEpisodeTrack(position int, episode api.Episode) export.Track {
+	track := export.Track{
+		Position:    position,
+		Type:        export.TypeEpisode,
+		Id:          episode.Id,
+		Uri:         episode.Uri,
+		Name:        episode.Name,
+		ReleaseDate: episode.ReleaseDate,
+		DurationMs:  episode.DurationMs,
+		Explicit:    episode.Explicit,
+	}
+	if episode.Show != nil {
+		track.Artists = []string{episode.Show.Publisher}
+		track.Album = episode.Show.Name
+		track.AlbumId = episode.Show.Id
+	}
+
+	return track
+}
+
+// ToLocalTrack exports a local file, which has a uri but no ids
+func ToLocalTrack(position int, local api.LocalFile) export.Track {
+	track := ToTrack(position, local.Track)
+	track.Type = export.TypeLocal
+
+	return track
+}
+
+// ToShowTrack exports a show as a single track, the publisher being the artist
+func ToShowTrack(position int, show api.SimplifiedShow) export.Track {
+	return export.Track{
+		Position: position,
+		Id:       show.Id,
+		Uri:      show.Uri,
+		Name:     show.Name,
+		Artists:  []string{show.Publisher},
+		Explicit: show.Explicit,
+	}
+}
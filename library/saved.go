@@ -0,0 +1,115 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Virtual playlists holding the saved items of the user
+var (
+	LikedSongs    = export.Playlist{Id: "liked-songs", Name: "Liked Songs"}
+	SavedAlbums   = export.Playlist{Id: "saved-albums", Name: "Saved Albums"}
+	SavedShows    = export.Playlist{Id: "saved-shows", Name: "Saved Shows"}
+	SavedEpisodes = export.Playlist{Id: "saved-episodes", Name: "Saved Episodes"}
+)
+
+// collections are the virtual playlists by name
+var collections = map[string]export.Playlist{
+	"tracks":   LikedSongs,
+	"albums":   SavedAlbums,
+	"shows":    SavedShows,
+	"episodes": SavedEpisodes,
+}
+
+// Collections returns the virtual playlists of the named saved items, one of
+// tracks, albums, shows or episodes
+func Collections(names []string) ([]export.Playlist, error) {
+	playlists := make([]export.Playlist, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		playlist, ok := collections[name]
+		if !ok {
+			available := make([]string, 0, len(collections))
+			for name := range collections {
+				available = append(available, name)
+			}
+			sort.Strings(available)
+
+			return nil, fmt.Errorf("unknown saved items %s, expected one of %s", name, strings.Join(available, ", "))
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	return playlists, nil
+}
+
+// IsCollection reports whether the playlist id is the one of a virtual playlist
+func IsCollection(id string) bool {
+	for _, playlist := range collections {
+		if playlist.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// collectionTracks streams the items of a virtual playlist, added_at being
+// the date the item was saved. Albums are expanded into their tracks.
+func (s *Source) collectionTracks(playlist export.Playlist, handle func(export.Track) error) error {
+	position := 0
+	next := func(track export.Track) error {
+		track.Position = position
+		position++
+		return handle(track)
+	}
+
+	switch playlist.Id {
+	case LikedSongs.Id:
+		return s.SavedTracks(handle)
+	case SavedAlbums.Id:
+		return s.client.SavedAlbums(func(saved api.SavedAlbum) error {
+			return s.albumTracks(saved, next)
+		})
+	case SavedShows.Id:
+		return s.client.SavedShows(func(saved api.SavedShow) error {
+			track := ToShowTrack(0, saved.Show)
+			track.AddedAt = saved.AddedAt
+			return next(track)
+		})
+	case SavedEpisodes.Id:
+		return s.client.SavedEpisodes(func(saved api.SavedEpisode) error {
+			track := ToEpisodeTrack(0, saved.Episode)
+			track.AddedAt = saved.AddedAt
+			return next(track)
+		})
+	default:
+		return fmt.Errorf("unknown virtual playlist %s", playlist.Id)
+	}
+}
+
+// albumTracks streams the tracks of a saved album, only fetching them when
+// the album holds more than its first page
+func (s *Source) albumTracks(saved api.SavedAlbum, handle func(export.Track) error) error {
+	convert := func(track api.Track) error {
+		track.Album = saved.Album.SimplifiedAlbum
+		converted := ToTrack(0, track)
+		converted.AddedAt = saved.AddedAt
+		return handle(converted)
+	}
+
+	if saved.Album.Tracks.Total <= len(saved.Album.Tracks.Items) {
+		for _, track := range saved.Album.Tracks.Items {
+			if err := convert(track); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return s.client.AlbumTracks(saved.Album.Id, convert)
+}
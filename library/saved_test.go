@@ -0,0 +1,106 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestCollections(t *testing.T) {
+	t.Run("it should return the virtual playlists by name", func(t *testing.T) {
+		playlists, err := Collections([]string{"albums", "tracks"})
+
+		if err != nil || len(playlists) != 2 || playlists[0] != SavedAlbums || playlists[1] != LikedSongs {
+			t.Errorf("Unexpected collections %+v, %v", playlists, err)
+		}
+	})
+
+	t.Run("it should reject an unknown name", func(t *testing.T) {
+		if _, err := Collections([]string{"artists"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestSourceCollections(t *testing.T) {
+	addedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("it should stream the liked songs as a playlist", func(t *testing.T) {
+		// Given a client with saved tracks
+		source := NewSource(&mockClient{savedTracks: []api.SavedTrack{
+			{AddedAt: addedAt, Track: api.Track{Id: "t1"}},
+		}})
+
+		// When streaming the liked songs
+		tracks := collect(t, source, LikedSongs)
+
+		// Then the saved tracks are returned with their added date
+		if len(tracks) != 1 || tracks[0].Id != "t1" || !tracks[0].AddedAt.Equal(addedAt) {
+			t.Errorf("Unexpected tracks %+v", tracks)
+		}
+	})
+
+	t.Run("it should expand the saved albums into their tracks", func(t *testing.T) {
+		// Given a complete album and one whose tracks span several pages
+		complete := api.SavedAlbum{AddedAt: addedAt}
+		complete.Album.Id = "a1"
+		complete.Album.Name = "First"
+		complete.Album.Tracks = api.Page[api.Track]{Items: []api.Track{{Id: "t1"}}, Total: 1}
+		paged := api.SavedAlbum{AddedAt: addedAt}
+		paged.Album.Id = "a2"
+		paged.Album.Name = "Second"
+		paged.Album.Tracks = api.Page[api.Track]{Items: []api.Track{{Id: "t2"}}, Total: 2}
+		source := NewSource(&mockClient{
+			savedAlbums: []api.SavedAlbum{complete, paged},
+			albumTracks: map[string][]api.Track{"a2": {{Id: "t2"}, {Id: "t3"}}},
+		})
+
+		// When streaming the saved albums
+		tracks := collect(t, source, SavedAlbums)
+
+		// Then the tracks of both albums are returned with the saved date
+		if len(tracks) != 3 || tracks[2].Id != "t3" || tracks[2].Position != 2 {
+			t.Fatalf("Unexpected tracks %+v", tracks)
+		}
+		if tracks[0].Album != "First" || tracks[1].AlbumId != "a2" || !tracks[2].AddedAt.Equal(addedAt) {
+			t.Errorf("Unexpected album details %+v", tracks)
+		}
+	})
+
+	t.Run("it should stream the saved shows and episodes", func(t *testing.T) {
+		// Given a saved show and a saved episode of it
+		show := api.SimplifiedShow{Id: "s1", Name: "Show", Publisher: "Publisher"}
+		source := NewSource(&mockClient{
+			savedShows:    []api.SavedShow{{AddedAt: addedAt, Show: show}},
+			savedEpisodes: []api.SavedEpisode{{AddedAt: addedAt, Episode: api.Episode{Id: "e1", DurationMs: 60000, Show: &show}}},
+		})
+
+		// When streaming them
+		shows := collect(t, source, SavedShows)
+		episodes := collect(t, source, SavedEpisodes)
+
+		// Then the show and the episode are published by the publisher
+		if len(shows) != 1 || shows[0].Id != "s1" || shows[0].Artists[0] != "Publisher" {
+			t.Errorf("Unexpected shows %+v", shows)
+		}
+		if len(episodes) != 1 || episodes[0].Album != "Show" || episodes[0].DurationMs != 60000 || !episodes[0].AddedAt.Equal(addedAt) {
+			t.Errorf("Unexpected episodes %+v", episodes)
+		}
+	})
+}
+
+// Helpers
+func collect(t *testing.T, source *Source, playlist export.Playlist) []export.Track {
+	var tracks []export.Track
+	err := source.Tracks(playlist, func(track export.Track) error {
+		tracks = append(tracks, track)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	return tracks
+}
@@ -0,0 +1,86 @@
+package library
+
+import (
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+type Client interface {
+	CurrentUserPlaylists(handle func(api.SimplifiedPlaylist) error) error
+	PlaylistItems(playlistId string, handle func(api.PlaylistItem) error) error
+	SavedTracks(handle func(api.SavedTrack) error) error
+	SavedAlbums(handle func(api.SavedAlbum) error) error
+	SavedShows(handle func(api.SavedShow) error) error
+	SavedEpisodes(handle func(api.SavedEpisode) error) error
+	AlbumTracks(albumId string, handle func(api.Track) error) error
+	FollowedArtists(handle func(api.Artist) error) error
+}
+
+// Source exposes the library of the current user to the export pipeline
+type Source struct {
+	client Client
+}
+
+func NewSource(client Client) *Source {
+	return &Source{client}
+}
+
+// Playlists returns both the owned and the followed playlists
+func (s *Source) Playlists() ([]export.Playlist, error) {
+	var playlists []export.Playlist
+	err := s.client.CurrentUserPlaylists(func(playlist api.SimplifiedPlaylist) error {
+		playlists = append(playlists, ToPlaylist(playlist))
+		return nil
+	})
+
+	return playlists, err
+}
+
+// Tracks streams the tracks, episodes and local files of a playlist,
+// unavailable items are skipped but still count towards the position.
+// Virtual playlists stream the saved items.
+func (s *Source) Tracks(playlist export.Playlist, handle func(export.Track) error) error {
+	if IsCollection(playlist.Id) {
+		return s.collectionTracks(playlist, handle)
+	}
+
+	position := 0
+	return s.client.PlaylistItems(playlist.Id, func(item api.PlaylistItem) error {
+		defer func() { position++ }()
+
+		var track export.Track
+		switch item := item.Item.(type) {
+		case *api.Track:
+			track = ToTrack(position, *item)
+		case *api.Episode:
+			track = ToEpisodeTrack(position, *item)
+		case *api.LocalFile:
+			track = ToLocalTrack(position, *item)
+		default:
+			return nil
+		}
+		track.AddedAt = item.AddedAt
+		track.AddedBy = item.AddedBy.Id
+
+		return handle(track)
+	})
+}
+
+func (s *Source) SavedTracks(handle func(export.Track) error) error {
+	position := 0
+	return s.client.SavedTracks(func(saved api.SavedTrack) error {
+		track := ToTrack(position, saved.Track)
+		track.AddedAt = saved.AddedAt
+		position++
+
+		return handle(track)
+	})
+}
+
+func (s *Source) SavedAlbums(handle func(api.SavedAlbum) error) error {
+	return s.client.SavedAlbums(handle)
+}
+
+func (s *Source) FollowedArtists(handle func(api.Artist) error) error {
+	return s.client.FollowedArtists(handle)
+}
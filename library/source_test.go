@@ -0,0 +1,195 @@
+package library
+
+import (
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Client
+type mockClient struct {
+	playlists     []api.SimplifiedPlaylist
+	items         map[string][]api.PlaylistItem
+	savedTracks   []api.SavedTrack
+	savedAlbums   []api.SavedAlbum
+	albumTracks   map[string][]api.Track
+	savedShows    []api.SavedShow
+	savedEpisodes []api.SavedEpisode
+}
+
+func (m *mockClient) CurrentUserPlaylists(handle func(api.SimplifiedPlaylist) error) error {
+	for _, playlist := range m.playlists {
+		if err := handle(playlist); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) PlaylistItems(playlistId string, handle func(api.PlaylistItem) error) error {
+	for _, item := range m.items[playlistId] {
+		if err := handle(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) SavedTracks(handle func(api.SavedTrack) error) error {
+	for _, saved := range m.savedTracks {
+		if err := handle(saved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) SavedAlbums(handle func(api.SavedAlbum) error) error {
+	for _, saved := range m.savedAlbums {
+		if err := handle(saved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) SavedShows(handle func(api.SavedShow) error) error {
+	for _, saved := range m.savedShows {
+		if err := handle(saved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) SavedEpisodes(handle func(api.SavedEpisode) error) error {
+	for _, saved := range m.savedEpisodes {
+		if err := handle(saved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) AlbumTracks(albumId string, handle func(api.Track) error) error {
+	for _, track := range m.albumTracks[albumId] {
+		if err := handle(track); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockClient) FollowedArtists(handle func(api.Artist) error) error {
+	return nil
+}
+
+func TestSource(t *testing.T) {
+	addedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("it should convert the playlists", func(t *testing.T) {
+		// Given a client with a playlist
+		playlist := api.SimplifiedPlaylist{Id: "p1", Name: "Name", SnapshotId: "s1", Collaborative: true}
+		playlist.Owner.Id = "owner"
+		playlist.Tracks.Total = 12
+		source := NewSource(&mockClient{playlists: []api.SimplifiedPlaylist{playlist}})
+
+		// When listing the playlists
+		playlists, err := source.Playlists()
+
+		// Then it should be converted
+		if err != nil {
+			t.Fatalf("Playlists returned an error: %s", err.Error())
+		}
+		expected := export.Playlist{Id: "p1", Name: "Name", Owner: "owner", Collaborative: true, SnapshotId: "s1", TrackCount: 12}
+		if len(playlists) != 1 || playlists[0] != expected {
+			t.Errorf("Expected %+v, got %+v", expected, playlists)
+		}
+	})
+
+	t.Run("it should stream the tracks keeping the playlist positions", func(t *testing.T) {
+		// Given a playlist with an unavailable track in the middle
+		track := api.Track{
+			Id:      "t1",
+			Name:    "Song",
+			Artists: []api.SimplifiedArtist{{Name: "Artist 1"}, {Name: "Artist 2"}},
+			Album:   api.SimplifiedAlbum{Name: "Album", ReleaseDate: "1999"},
+		}
+		track.ExternalIds.Isrc = "ISRC"
+		source := NewSource(&mockClient{items: map[string][]api.PlaylistItem{
+			"p1": {
+				{AddedAt: addedAt, AddedBy: api.User{Id: "adder"}, Item: &track},
+				{Item: api.Unavailable{}},
+				{Item: &track},
+			},
+		}})
+
+		// When streaming the tracks
+		var tracks []export.Track
+		err := source.Tracks(export.Playlist{Id: "p1"}, func(track export.Track) error {
+			tracks = append(tracks, track)
+			return nil
+		})
+
+		// Then the unavailable track should be skipped
+		if err != nil {
+			t.Fatalf("Tracks returned an error: %s", err.Error())
+		}
+		if len(tracks) != 2 || tracks[0].Position != 0 || tracks[1].Position != 2 {
+			t.Fatalf("Unexpected tracks %+v", tracks)
+		}
+
+		// and the track converted
+		first := tracks[0]
+		if first.Album != "Album" || first.ReleaseDate != "1999" || first.Isrc != "ISRC" ||
+			first.AddedBy != "adder" || !first.AddedAt.Equal(addedAt) || len(first.Artists) != 2 {
+			t.Errorf("Unexpected track %+v", first)
+		}
+	})
+
+	t.Run("it should export the episodes and the local files with their type", func(t *testing.T) {
+		// Given a playlist with an episode and a local file
+		local := api.LocalFile{Track: api.Track{Uri: "spotify:local:Artist:Album:Title:180", Name: "Title"}}
+		show := api.SimplifiedShow{Id: "s1", Name: "Show", Publisher: "Publisher"}
+		source := NewSource(&mockClient{items: map[string][]api.PlaylistItem{
+			"p1": {
+				{Item: &api.Episode{Id: "e1", Name: "Episode", Show: &show}},
+				{Item: &local},
+			},
+		}})
+
+		// When streaming the tracks
+		var tracks []export.Track
+		source.Tracks(export.Playlist{Id: "p1"}, func(track export.Track) error {
+			tracks = append(tracks, track)
+			return nil
+		})
+
+		// Then both should be exported with their type
+		if len(tracks) != 2 || tracks[0].Type != export.TypeEpisode || tracks[0].Album != "Show" || tracks[0].Artists[0] != "Publisher" {
+			t.Fatalf("Unexpected tracks %+v", tracks)
+		}
+		if tracks[1].Type != export.TypeLocal || tracks[1].Id != "" || tracks[1].Uri != local.Uri || tracks[1].Position != 1 {
+			t.Errorf("Unexpected local file %+v", tracks[1])
+		}
+	})
+
+	t.Run("it should stream the saved tracks with their added date", func(t *testing.T) {
+		source := NewSource(&mockClient{savedTracks: []api.SavedTrack{
+			{AddedAt: addedAt, Track: api.Track{Id: "t1"}},
+			{AddedAt: addedAt, Track: api.Track{Id: "t2"}},
+		}})
+
+		var tracks []export.Track
+		source.SavedTracks(func(track export.Track) error {
+			tracks = append(tracks, track)
+			return nil
+		})
+
+		if len(tracks) != 2 || tracks[1].Id != "t2" || tracks[1].Position != 1 || !tracks[1].AddedAt.Equal(addedAt) {
+			t.Errorf("Unexpected saved tracks %+v", tracks)
+		}
+	})
+}
@@ -1,5 +1,11 @@
 package main
 
+import (
+	"os"
+
+	"prisco.dev/spotify-playlist/cli"
+)
+
 func main() {
-	println("Hello world")
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
 }
@@ -0,0 +1,171 @@
+package matcher
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDurationTolerance is the duration difference under which two tracks
+// are considered of the same length
+const DefaultDurationTolerance = 3 * time.Second
+
+const (
+	titleWeight    = 0.5
+	artistsWeight  = 0.35
+	durationWeight = 0.15
+)
+
+type Track struct {
+	Id         string
+	Title      string
+	Artists    []string
+	DurationMs int
+	Isrc       string
+}
+
+type Result struct {
+	Track Track
+	// Index is the position of the candidate in the slice given to Rank
+	Index int
+	// Score goes from 0 to 1, 1 being the same recording
+	Score   float64
+	Reasons []string
+}
+
+type Matcher struct {
+	// DurationTolerance is the difference accepted without penalty, the
+	// duration score then decreases to 0 at five times the tolerance
+	DurationTolerance time.Duration
+}
+
+func NewMatcher() *Matcher {
+	return &Matcher{DurationTolerance: DefaultDurationTolerance}
+}
+
+// Score compares the candidate to the query. A shared ISRC is a certain
+// match, otherwise title, artists and duration are weighted, ignoring the
+// ones unknown on either side.
+func (m *Matcher) Score(query Track, candidate Track) Result {
+	result := Result{Track: candidate}
+
+	if query.Isrc != "" && strings.EqualFold(query.Isrc, candidate.Isrc) {
+		result.Score = 1
+		result.Reasons = append(result.Reasons, "same ISRC")
+		return result
+	}
+
+	total, weights := 0.0, 0.0
+
+	titleScore := wordSimilarity(NormalizeTitle(query.Title), NormalizeTitle(candidate.Title))
+	total += titleWeight * titleScore
+	weights += titleWeight
+	if titleScore == 1 {
+		result.Reasons = append(result.Reasons, "same title")
+	} else {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("title %.0f%% similar", titleScore*100))
+	}
+
+	queryArtists := artistSet(query)
+	candidateArtists := artistSet(candidate)
+	if len(queryArtists) > 0 && len(candidateArtists) > 0 {
+		common := 0
+		for artist := range queryArtists {
+			if candidateArtists[artist] {
+				common++
+			}
+		}
+
+		// Featured artists are often credited on one side only, so only the
+		// artists of the side crediting fewer are expected in common
+		credited := min(len(queryArtists), len(candidateArtists))
+		artistsScore := float64(common) / float64(credited)
+		total += artistsWeight * artistsScore
+		weights += artistsWeight
+		reason := fmt.Sprintf("%d of %d artists in common", common, credited)
+		if extra := max(len(queryArtists), len(candidateArtists)) - credited; extra > 0 {
+			reason += fmt.Sprintf(", %d more credited on one side only", extra)
+		}
+		result.Reasons = append(result.Reasons, reason)
+	}
+
+	if query.DurationMs > 0 && candidate.DurationMs > 0 {
+		delta := time.Duration(math.Abs(float64(query.DurationMs-candidate.DurationMs))) * time.Millisecond
+		durationScore := 1.0
+		if delta > m.DurationTolerance {
+			durationScore = math.Max(0, 1-float64(delta-m.DurationTolerance)/float64(4*m.DurationTolerance))
+		}
+
+		total += durationWeight * durationScore
+		weights += durationWeight
+		result.Reasons = append(result.Reasons, fmt.Sprintf("duration differs by %s", delta.Round(time.Second)))
+	}
+
+	if query.Isrc != "" && candidate.Isrc != "" {
+		result.Reasons = append(result.Reasons, "different ISRC")
+	}
+
+	result.Score = total / weights
+	return result
+}
+
+// Rank scores every candidate and sorts them from the best match
+func (m *Matcher) Rank(query Track, candidates []Track) []Result {
+	results := make([]Result, 0, len(candidates))
+	for i, candidate := range candidates {
+		result := m.Score(query, candidate)
+		result.Index = i
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// artistSet includes the artists featured in the title
+func artistSet(track Track) map[string]bool {
+	artists := map[string]bool{}
+	for _, artist := range track.Artists {
+		if artist = NormalizeArtist(artist); artist != "" {
+			artists[artist] = true
+		}
+	}
+	for _, artist := range FeaturedArtists(track.Title) {
+		artists[artist] = true
+	}
+
+	return artists
+}
+
+// wordSimilarity is the Dice coefficient of the words of both values
+func wordSimilarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	wordsA := strings.Fields(a)
+	wordsB := strings.Fields(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, word := range wordsA {
+		counts[word]++
+	}
+
+	common := 0
+	for _, word := range wordsB {
+		if counts[word] > 0 {
+			counts[word]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
@@ -0,0 +1,121 @@
+package matcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcher_Score(t *testing.T) {
+	matcher := NewMatcher()
+
+	tests := []struct {
+		name      string
+		query     Track
+		candidate Track
+		minScore  float64
+		maxScore  float64
+	}{
+		{
+			"same isrc on single and album release",
+			Track{Title: "Song", Isrc: "GBAYE0601498"},
+			Track{Title: "Song - Single Version", Isrc: "gbaye0601498"},
+			1, 1,
+		},
+		{
+			"remastered release of the same song",
+			Track{Title: "Help!", Artists: []string{"The Beatles"}, DurationMs: 138000},
+			Track{Title: "Help! - Remastered 2009", Artists: []string{"Beatles"}, DurationMs: 139000},
+			1, 1,
+		},
+		{
+			"featured artist credited in the title only",
+			Track{Title: "Get Lucky (feat. Pharrell Williams)", Artists: []string{"Daft Punk"}, DurationMs: 369000},
+			Track{Title: "Get Lucky", Artists: []string{"Daft Punk", "Pharrell Williams"}, DurationMs: 367000},
+			1, 1,
+		},
+		{
+			"diacritics and punctuation",
+			Track{Title: "Senorita", Artists: []string{"Beyonce"}},
+			Track{Title: "Señorita!", Artists: []string{"Beyoncé"}},
+			1, 1,
+		},
+		{
+			"small duration delta",
+			Track{Title: "Song", Artists: []string{"Artist"}, DurationMs: 200000},
+			Track{Title: "Song", Artists: []string{"Artist"}, DurationMs: 206000},
+			0.95, 0.99,
+		},
+		{
+			"large duration delta of an extended mix",
+			Track{Title: "Song", Artists: []string{"Artist"}, DurationMs: 200000},
+			Track{Title: "Song", Artists: []string{"Artist"}, DurationMs: 420000},
+			0.85, 0.85,
+		},
+		{
+			"same title by another artist",
+			Track{Title: "Hurt", Artists: []string{"Nine Inch Nails"}},
+			Track{Title: "Hurt", Artists: []string{"Johnny Cash"}},
+			0.58, 0.59,
+		},
+		{
+			"different song by the same artist",
+			Track{Title: "Yesterday", Artists: []string{"The Beatles"}},
+			Track{Title: "Let It Be", Artists: []string{"The Beatles"}},
+			0.41, 0.42,
+		},
+		{
+			"karaoke version",
+			Track{Title: "Under Pressure", Artists: []string{"Queen"}, DurationMs: 248000},
+			Track{Title: "Under Pressure - Karaoke Version", Artists: []string{"Karaoke Band"}, DurationMs: 250000},
+			0.6, 0.7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := matcher.Score(test.query, test.candidate)
+
+			if result.Score < test.minScore || result.Score > test.maxScore {
+				t.Errorf("Expected a score between %.2f and %.2f, got %.4f (%v)", test.minScore, test.maxScore, result.Score, result.Reasons)
+			}
+		})
+	}
+}
+
+func TestMatcher_Reasons(t *testing.T) {
+	// Given two releases of a song with different ISRCs
+	query := Track{Title: "Song", Artists: []string{"A", "B"}, DurationMs: 200000, Isrc: "ISRC1"}
+	candidate := Track{Title: "Song (Remastered)", Artists: []string{"A"}, DurationMs: 204400, Isrc: "ISRC2"}
+
+	// When scoring them
+	result := NewMatcher().Score(query, candidate)
+
+	// Then every criteria should be explained
+	expected := []string{"same title", "1 of 1 artists in common, 1 more credited on one side only", "duration differs by 4s", "different ISRC"}
+	if !reflect.DeepEqual(result.Reasons, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Reasons)
+	}
+}
+
+func TestMatcher_Rank(t *testing.T) {
+	// Given candidates from a search
+	query := Track{Title: "Under Pressure", Artists: []string{"Queen", "David Bowie"}, DurationMs: 248000}
+	candidates := []Track{
+		{Id: "karaoke", Title: "Under Pressure - Karaoke", Artists: []string{"Karaoke Band"}, DurationMs: 248000},
+		{Id: "cover", Title: "Under Pressure", Artists: []string{"My Chemical Romance"}, DurationMs: 212000},
+		{Id: "original", Title: "Under Pressure - Remastered 2011", Artists: []string{"Queen", "David Bowie"}, DurationMs: 248000},
+	}
+
+	// When ranking them
+	results := NewMatcher().Rank(query, candidates)
+
+	// Then the original should come first
+	if len(results) != 3 || results[0].Track.Id != "original" || results[0].Index != 2 {
+		t.Fatalf("Expected the original first, got %+v", results)
+	}
+
+	// and the others sorted by score
+	if results[1].Score < results[2].Score {
+		t.Errorf("Expected results sorted by score, got %+v", results)
+	}
+}
@@ -0,0 +1,96 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// Suffixes such as " - Remastered 2011" or " - Single Version"
+	versionSuffix = regexp.MustCompile(`\s+-\s+.*\b(remaster(ed)?|version|edit|mono|stereo|live|deluxe|bonus|explicit|clean)\b.*$`)
+	// Segments such as "(Remastered 2011)" or "[Deluxe Edition]"
+	versionSegment = regexp.MustCompile(`[(\[][^)\]]*\b(remaster(ed)?|version|mono|stereo|deluxe|bonus|explicit|clean)\b[^)\]]*[)\]]`)
+	// Featured artists as in "(feat. A)", "(with A)" or "feat. A" up to the end
+	featuringSegment = regexp.MustCompile(`[(\[]\s*(feat\.?|ft\.?|featuring|with)\s+([^)\]]+)[)\]]`)
+	featuringSuffix  = regexp.MustCompile(`(\s+-)?\s+\b(feat\.?|ft\.?|featuring)\s+(.+)$`)
+	artistSeparators = regexp.MustCompile(`\s*(,|&|\band\b|\bx\b|\bfeat\.?|\bft\.?)\s*`)
+)
+
+var diacritics = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ą", "a", "ă", "a",
+	"æ", "ae", "ç", "c", "ć", "c", "č", "c", "ď", "d", "đ", "d",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ę", "e", "ě", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+	"ł", "l", "ñ", "n", "ń", "n", "ň", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ō", "o", "ő", "o", "œ", "oe",
+	"ř", "r", "ś", "s", "š", "s", "ş", "s", "ß", "ss", "ť", "t", "ţ", "t",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+	"ý", "y", "ÿ", "y", "ź", "z", "ż", "z", "ž", "z",
+)
+
+// NormalizeTitle strips the version details and featured artists from a
+// title and folds it to lowercase ascii words, so that "Help! - Remastered
+// 2009" and "Help" compare equal
+func NormalizeTitle(title string) string {
+	title = strings.ToLower(title)
+	title = featuringSegment.ReplaceAllString(title, "")
+	title = featuringSuffix.ReplaceAllString(title, "")
+	title = versionSuffix.ReplaceAllString(title, "")
+	title = versionSegment.ReplaceAllString(title, "")
+
+	return normalizeWords(title)
+}
+
+// NormalizeArtist folds an artist name to lowercase ascii words, a leading
+// "the" is dropped
+func NormalizeArtist(artist string) string {
+	return strings.TrimPrefix(normalizeWords(strings.ToLower(artist)), "the ")
+}
+
+// FeaturedArtists returns the artists credited in the title, as in
+// "Song (feat. A & B)"
+func FeaturedArtists(title string) []string {
+	title = strings.ToLower(title)
+
+	var credits []string
+	for _, match := range featuringSegment.FindAllStringSubmatch(title, -1) {
+		credits = append(credits, match[2])
+	}
+	title = featuringSegment.ReplaceAllString(title, "")
+	if match := featuringSuffix.FindStringSubmatch(title); match != nil {
+		// Drop the version details following the credit, as in "feat. A - Remastered"
+		credit, _, _ := strings.Cut(match[3], " - ")
+		credits = append(credits, credit)
+	}
+
+	var artists []string
+	for _, credit := range credits {
+		for _, artist := range artistSeparators.Split(credit, -1) {
+			if artist = NormalizeArtist(artist); artist != "" {
+				artists = append(artists, artist)
+			}
+		}
+	}
+
+	return artists
+}
+
+func normalizeWords(value string) string {
+	value = diacritics.Replace(value)
+	value = strings.ReplaceAll(value, "&", " and ")
+
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '\'' || r == '’':
+			// "don't" and "dont" are the same word
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
@@ -0,0 +1,73 @@
+package matcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"Help!", "help"},
+		{"Help! - Remastered 2009", "help"},
+		{"Here Comes The Sun - 2019 Mix", "here comes the sun 2019 mix"},
+		{"Wish You Were Here (Remastered 2011)", "wish you were here"},
+		{"Heroes - 2017 Remaster", "heroes"},
+		{"Don't Stop Me Now - Remastered 2011", "dont stop me now"},
+		{"Señorita", "senorita"},
+		{"Björk's Song [Deluxe Edition]", "bjorks song"},
+		{"Get Lucky (feat. Pharrell Williams & Nile Rodgers)", "get lucky"},
+		{"Get Lucky feat. Pharrell Williams - Radio Edit", "get lucky"},
+		{"Stay (with Justin Bieber)", "stay"},
+		{"Dancing with Myself", "dancing with myself"},
+		{"Rock & Roll", "rock and roll"},
+		{"Under Pressure - Single Version", "under pressure"},
+		{"Smells Like Teen Spirit (Live)", "smells like teen spirit live"},
+		{"Sweet Child O' Mine", "sweet child o mine"},
+	}
+
+	for _, test := range tests {
+		if normalized := NormalizeTitle(test.title); normalized != test.expected {
+			t.Errorf("Expected '%s' to normalize to '%s', got '%s'", test.title, test.expected, normalized)
+		}
+	}
+}
+
+func TestNormalizeArtist(t *testing.T) {
+	tests := []struct {
+		artist   string
+		expected string
+	}{
+		{"The Beatles", "beatles"},
+		{"Beyoncé", "beyonce"},
+		{"Simon & Garfunkel", "simon and garfunkel"},
+		{"AC/DC", "ac dc"},
+	}
+
+	for _, test := range tests {
+		if normalized := NormalizeArtist(test.artist); normalized != test.expected {
+			t.Errorf("Expected '%s' to normalize to '%s', got '%s'", test.artist, test.expected, normalized)
+		}
+	}
+}
+
+func TestFeaturedArtists(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected []string
+	}{
+		{"Get Lucky (feat. Pharrell Williams & Nile Rodgers)", []string{"pharrell williams", "nile rodgers"}},
+		{"Song ft. A, B and C - Remastered", []string{"a", "b", "c"}},
+		{"Stay (with Justin Bieber)", []string{"justin bieber"}},
+		{"Dancing with Myself", nil},
+		{"Plain", nil},
+	}
+
+	for _, test := range tests {
+		if artists := FeaturedArtists(test.title); !reflect.DeepEqual(artists, test.expected) {
+			t.Errorf("Expected %v featured in '%s', got %v", test.expected, test.title, artists)
+		}
+	}
+}
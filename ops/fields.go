@@ -0,0 +1,62 @@
+package ops
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+)
+
+// Fields exposes a track to the expressions used to sort and filter, fields
+// which are unknown for the track are nil
+func Fields(track export.Track) expr.Env {
+	env := expr.Env{
+		"type":         track.Type,
+		"id":           track.Id,
+		"uri":          track.Uri,
+		"name":         track.Name,
+		"artist":       nil,
+		"artists":      strings.Join(track.Artists, ", "),
+		"album":        track.Album,
+		"release_date": track.ReleaseDate,
+		"year":         nil,
+		"duration":     float64(track.DurationMs) / 1000,
+		"duration_ms":  track.DurationMs,
+		"popularity":   track.Popularity,
+		"explicit":     track.Explicit,
+		"isrc":         track.Isrc,
+		"position":     track.Position,
+		"added_by":     track.AddedBy,
+		"added_at":     nil,
+	}
+
+	if len(track.Artists) > 0 {
+		env["artist"] = track.Artists[0]
+	}
+	if len(track.ReleaseDate) >= 4 {
+		if year, err := strconv.Atoi(track.ReleaseDate[:4]); err == nil {
+			env["year"] = year
+		}
+	}
+	for _, name := range export.AudioFeatureColumns {
+		env[name] = nil
+	}
+	if f := track.AudioFeatures; f != nil {
+		env["tempo"] = f.Tempo
+		env["energy"] = f.Energy
+		env["danceability"] = f.Danceability
+		env["valence"] = f.Valence
+		env["loudness"] = f.Loudness
+		env["key"] = f.Key
+		env["mode"] = f.Mode
+		env["time_signature"] = f.TimeSignature
+	}
+	if !track.AddedAt.IsZero() {
+		// RFC3339 in UTC sorts chronologically
+		env["added_at"] = track.AddedAt.UTC().Format(time.RFC3339)
+	}
+
+	return env
+}
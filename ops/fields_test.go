@@ -0,0 +1,43 @@
+package ops
+
+import (
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestFields(t *testing.T) {
+	t.Run("it should expose the track fields", func(t *testing.T) {
+		fields := Fields(export.Track{
+			Name:        "Song",
+			Artists:     []string{"A", "B"},
+			ReleaseDate: "1999-12",
+			DurationMs:  90500,
+			AddedAt:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600)),
+		})
+
+		expected := map[string]any{
+			"artist":   "A",
+			"artists":  "A, B",
+			"year":     1999,
+			"duration": 90.5,
+			"added_at": "2024-01-02T02:04:05Z",
+		}
+		for name, value := range expected {
+			if fields[name] != value {
+				t.Errorf("Expected %s to be %v, got %v", name, value, fields[name])
+			}
+		}
+	})
+
+	t.Run("it should set the unknown fields to nil", func(t *testing.T) {
+		fields := Fields(export.Track{ReleaseDate: "n/a"})
+
+		for _, name := range []string{"artist", "year", "added_at"} {
+			if value, ok := fields[name]; !ok || value != nil {
+				t.Errorf("Expected %s to be nil, got %v", name, value)
+			}
+		}
+	})
+}
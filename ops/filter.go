@@ -0,0 +1,31 @@
+package ops
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+)
+
+// Filter plans a playlist with the tracks matching the condition, features
+// are only used when the condition references audio features
+func Filter(entry export.PlaylistEntry, condition *expr.Expression, name string, features FeatureClient) (Plan, error) {
+	envs, err := environments(entry.Tracks, condition, features)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{Playlist: export.Playlist{Name: name}}
+	for i, track := range entry.Tracks {
+		keep, err := condition.EvalBool(envs[i])
+		if err != nil {
+			return Plan{}, fmt.Errorf("failed to evaluate %q for track %s: %w", condition, track.Id, err)
+		}
+
+		if keep {
+			plan.Tracks = append(plan.Tracks, track)
+		}
+	}
+
+	return plan, nil
+}
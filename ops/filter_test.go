@@ -0,0 +1,49 @@
+package ops
+
+import (
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+)
+
+func TestFilter(t *testing.T) {
+	entry := createEntry(
+		export.Track{Id: "new", ReleaseDate: "2004"},
+		export.Track{Id: "explicit", ReleaseDate: "2010", Explicit: true},
+		export.Track{Id: "old", ReleaseDate: "1990"},
+		export.Track{Id: "unknown"},
+	)
+
+	t.Run("it should keep the tracks matching the condition", func(t *testing.T) {
+		condition, _ := expr.Parse("year >= 2000 && !explicit")
+
+		plan, err := Filter(entry, condition, "Clean", nil)
+
+		if err != nil {
+			t.Fatalf("Filter returned an error: %s", err.Error())
+		}
+		if ids := uris(plan.entry()); plan.Playlist.Name != "Clean" || len(ids) != 1 || ids[0] != "new" {
+			t.Errorf("Unexpected plan %s %v", plan.Playlist.Name, ids)
+		}
+	})
+
+	t.Run("it should filter on audio features", func(t *testing.T) {
+		condition, _ := expr.Parse("tempo > 120")
+		features := &mockFeatureClient{tempos: map[string]float64{"old": 140, "new": 90}}
+
+		plan, _ := Filter(entry, condition, "Fast", features)
+
+		if ids := uris(plan.entry()); len(ids) != 1 || ids[0] != "old" {
+			t.Errorf("Unexpected tracks %v", ids)
+		}
+	})
+
+	t.Run("it should return an error for non boolean conditions", func(t *testing.T) {
+		condition, _ := expr.Parse("year")
+
+		if _, err := Filter(entry, condition, "Invalid", nil); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
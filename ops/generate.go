@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/dedupe"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/library"
+)
+
+// generateRounds is the number of recommendation requests made to fill the
+// playlist once the tracks already in the source are left out
+const generateRounds = 3
+
+type RecommendationClient interface {
+	Recommendations(request api.RecommendationRequest) ([]api.Track, error)
+}
+
+type GenerateOptions struct {
+	// Seeds are the artists, tracks and genres to start from
+	Seeds api.RecommendationRequest
+	// Source provides the missing seeds, its most frequent artists, and its
+	// tracks are left out of the generated playlist
+	Source *export.PlaylistEntry
+	// Size is the number of tracks of the playlist
+	Size int
+	// Fuzzy also leaves out the different releases of the songs of the source
+	Fuzzy bool
+}
+
+// Generate plans a playlist of recommended tracks, without duplicates nor
+// tracks of the source. Every round seeds the recommendations with the next
+// artists of the source, stopping once they would repeat a request.
+func Generate(client RecommendationClient, name string, options GenerateOptions) (Plan, error) {
+	request := options.Seeds
+	request.Limit = api.MaxRecommendations
+
+	source := export.PlaylistEntry{}
+	var artists []string
+	free := api.MaxSeeds - len(request.SeedArtists) - len(request.SeedTracks) - len(request.SeedGenres)
+	if options.Source != nil {
+		source = *options.Source
+		artists = rankArtists(source.Tracks)
+	}
+
+	plan := Plan{Playlist: export.Playlist{Name: name}}
+	requested := map[string]bool{}
+	for round := 0; round < generateRounds && len(plan.Tracks) < options.Size; round++ {
+		request.SeedArtists = append(slices.Clone(options.Seeds.SeedArtists), rotate(artists, round*free, free)...)
+		key := strings.Join(request.SeedArtists, ",")
+		if requested[key] {
+			break
+		}
+		requested[key] = true
+
+		recommended, err := client.Recommendations(request)
+		if err != nil {
+			return Plan{}, fmt.Errorf("failed to get recommendations: %w", err)
+		}
+		if len(recommended) == 0 {
+			break
+		}
+
+		candidates := export.PlaylistEntry{Playlist: export.Playlist{Id: "generated"}}
+		for i, track := range recommended {
+			candidates.Tracks = append(candidates.Tracks, library.ToTrack(i, track))
+		}
+
+		// The planned tracks come first so that the later rounds only add new ones
+		planned := export.PlaylistEntry{Playlist: export.Playlist{Id: "planned"}, Tracks: plan.entry().Tracks}
+		duplicated := map[int]bool{}
+		for _, duplicate := range dedupe.Find([]export.PlaylistEntry{source, planned, candidates}, dedupe.Options{Fuzzy: options.Fuzzy}) {
+			if duplicate.Duplicate.Playlist.Id == candidates.Playlist.Id {
+				duplicated[duplicate.Duplicate.Track.Position] = true
+			}
+		}
+
+		for _, track := range candidates.Tracks {
+			if !duplicated[track.Position] && len(plan.Tracks) < options.Size {
+				plan.Tracks = append(plan.Tracks, track)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// rotate returns up to count ids starting from offset, wrapping around
+func rotate(ids []string, offset int, count int) []string {
+	var result []string
+	for i := 0; i < min(count, len(ids)); i++ {
+		result = append(result, ids[(offset+i)%len(ids)])
+	}
+
+	return result
+}
+
+// rankArtists returns the ids of the artists by number of tracks, the first
+// appearing first on a tie
+func rankArtists(tracks []export.Track) []string {
+	var ids []string
+	counts := map[string]int{}
+	for _, track := range tracks {
+		for _, id := range track.ArtistIds {
+			if id == "" {
+				continue
+			}
+			if counts[id] == 0 {
+				ids = append(ids, id)
+			}
+			counts[id]++
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		return counts[ids[i]] > counts[ids[j]]
+	})
+
+	return ids
+}
@@ -0,0 +1,109 @@
+package ops
+
+import (
+	"reflect"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Recommendation Client returning a batch of tracks per call
+type mockRecommendationClient struct {
+	batches  [][]string
+	requests []api.RecommendationRequest
+}
+
+func (m *mockRecommendationClient) Recommendations(request api.RecommendationRequest) ([]api.Track, error) {
+	m.requests = append(m.requests, request)
+	if len(m.batches) == 0 {
+		return nil, nil
+	}
+
+	var tracks []api.Track
+	for _, id := range m.batches[0] {
+		tracks = append(tracks, api.Track{Id: id, Uri: "spotify:track:" + id, Name: "Song " + id})
+	}
+	m.batches = m.batches[1:]
+	return tracks, nil
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("it should seed the recommendations with the top artists of the source", func(t *testing.T) {
+		// Given a source whose artist b has the most tracks
+		source := createEntry(
+			export.Track{Uri: "spotify:track:1", ArtistIds: []string{"a"}},
+			export.Track{Uri: "spotify:track:2", ArtistIds: []string{"b", "c"}},
+			export.Track{Uri: "spotify:track:3", ArtistIds: []string{"b"}},
+		)
+		client := &mockRecommendationClient{}
+
+		// When generating a playlist from a genre and the source
+		Generate(client, "Generated", GenerateOptions{
+			Seeds:  api.RecommendationRequest{SeedGenres: []string{"house", "disco", "funk"}},
+			Source: &source,
+			Size:   10,
+		})
+
+		// Then the free seeds should be the top artists
+		if len(client.requests) != 1 || !reflect.DeepEqual(client.requests[0].SeedArtists, []string{"b", "a"}) {
+			t.Errorf("Unexpected requests %+v", client.requests)
+		}
+		if client.requests[0].Limit != api.MaxRecommendations {
+			t.Errorf("Expected the maximum of recommendations, got %d", client.requests[0].Limit)
+		}
+	})
+
+	t.Run("it should leave out the tracks of the source and the duplicates", func(t *testing.T) {
+		// Given a source and recommendations including its tracks and repeating themselves
+		source := createEntry(
+			export.Track{Uri: "spotify:track:1", ArtistIds: []string{"a", "b"}},
+			export.Track{Uri: "spotify:track:2", ArtistIds: []string{"c", "d", "e"}},
+		)
+		client := &mockRecommendationClient{batches: [][]string{
+			{"1", "3", "4", "3"},
+			{"2", "4", "5", "6", "7"},
+		}}
+
+		// When generating a playlist of 4 tracks
+		plan, err := Generate(client, "Generated", GenerateOptions{
+			Seeds:  api.RecommendationRequest{SeedTracks: []string{"1"}},
+			Source: &source,
+			Size:   4,
+		})
+
+		// Then the playlist should be filled over two rounds with new tracks only
+		if err != nil || plan.Playlist.Name != "Generated" || len(client.requests) != 2 {
+			t.Fatalf("Unexpected plan %+v (%v)", plan, err)
+		}
+		if ids := uris(export.PlaylistEntry{Tracks: plan.Tracks}); !reflect.DeepEqual(ids, []string{"3", "4", "5", "6"}) {
+			t.Errorf("Unexpected tracks %v", ids)
+		}
+
+		// and the second round should be seeded with the next artists of the source
+		if !reflect.DeepEqual(client.requests[1].SeedArtists, []string{"e", "a", "b", "c"}) {
+			t.Errorf("Unexpected seeds of the second round %v", client.requests[1].SeedArtists)
+		}
+	})
+
+	t.Run("it should stop when nothing more is recommended", func(t *testing.T) {
+		source := createEntry(export.Track{Uri: "spotify:track:1", ArtistIds: []string{"a", "b", "c", "d", "e"}})
+		client := &mockRecommendationClient{batches: [][]string{{"2"}}}
+
+		plan, err := Generate(client, "Generated", GenerateOptions{Seeds: api.RecommendationRequest{SeedGenres: []string{"jazz"}}, Source: &source, Size: 10})
+
+		if err != nil || len(plan.Tracks) != 1 || len(client.requests) != 2 {
+			t.Errorf("Unexpected plan %+v after %d requests (%v)", plan, len(client.requests), err)
+		}
+	})
+
+	t.Run("it should not repeat a request without other seeds", func(t *testing.T) {
+		client := &mockRecommendationClient{batches: [][]string{{"1"}, {"2"}}}
+
+		plan, err := Generate(client, "Generated", GenerateOptions{Seeds: api.RecommendationRequest{SeedGenres: []string{"jazz"}}, Size: 10})
+
+		if err != nil || len(plan.Tracks) != 1 || len(client.requests) != 1 {
+			t.Errorf("Unexpected plan %+v after %d requests (%v)", plan, len(client.requests), err)
+		}
+	})
+}
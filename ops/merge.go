@@ -0,0 +1,38 @@
+package ops
+
+import (
+	"prisco.dev/spotify-playlist/dedupe"
+	"prisco.dev/spotify-playlist/export"
+)
+
+type MergeOptions struct {
+	// Dedupe keeps only the first occurrence of every track
+	Dedupe bool
+	// Fuzzy also drops different releases of the same song when deduping
+	Fuzzy bool
+}
+
+// Merge plans a playlist with the tracks of every entry, in order
+func Merge(entries []export.PlaylistEntry, name string, options MergeOptions) Plan {
+	duplicated := map[string]map[int]bool{}
+	if options.Dedupe {
+		for _, duplicate := range dedupe.Find(entries, dedupe.Options{Fuzzy: options.Fuzzy}) {
+			occurrence := duplicate.Duplicate
+			if duplicated[occurrence.Playlist.Id] == nil {
+				duplicated[occurrence.Playlist.Id] = map[int]bool{}
+			}
+			duplicated[occurrence.Playlist.Id][occurrence.Track.Position] = true
+		}
+	}
+
+	plan := Plan{Playlist: export.Playlist{Name: name}}
+	for _, entry := range entries {
+		for _, track := range entry.Tracks {
+			if !duplicated[entry.Playlist.Id][track.Position] {
+				plan.Tracks = append(plan.Tracks, track)
+			}
+		}
+	}
+
+	return plan
+}
@@ -0,0 +1,29 @@
+package ops
+
+import (
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestMerge(t *testing.T) {
+	first := createEntry(export.Track{Id: "a", Uri: "a"}, export.Track{Id: "b", Uri: "b"})
+	second := createEntry(export.Track{Id: "b", Uri: "b"}, export.Track{Id: "c", Uri: "c"})
+	second.Playlist.Id = "p2"
+
+	t.Run("it should concatenate the playlists", func(t *testing.T) {
+		plan := Merge([]export.PlaylistEntry{first, second}, "Merged", MergeOptions{})
+
+		if plan.Playlist.Name != "Merged" || len(plan.Tracks) != 4 {
+			t.Errorf("Unexpected plan %+v", plan)
+		}
+	})
+
+	t.Run("it should keep the first occurrence when deduping", func(t *testing.T) {
+		plan := Merge([]export.PlaylistEntry{first, second}, "Merged", MergeOptions{Dedupe: true})
+
+		if ids := uris(plan.entry()); len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "c" {
+			t.Errorf("Unexpected tracks %v", ids)
+		}
+	})
+}
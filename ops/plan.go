@@ -0,0 +1,63 @@
+package ops
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+// previewTracks is the number of tracks listed per playlist in a preview
+const previewTracks = 5
+
+// Plan is a playlist to be created by merge, split, filter or generate
+type Plan struct {
+	Playlist export.Playlist
+	Tracks   []export.Track
+}
+
+func (p Plan) entry() export.PlaylistEntry {
+	entry := export.PlaylistEntry{Playlist: p.Playlist}
+	for i, track := range p.Tracks {
+		track.Position = i
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	return entry
+}
+
+// PrintPlans previews the playlists which would be created
+func PrintPlans(writer io.Writer, plans []Plan) error {
+	var out strings.Builder
+	for _, plan := range plans {
+		fmt.Fprintf(&out, "%s (%d tracks)\n", plan.Playlist.Name, len(plan.Tracks))
+
+		for i, track := range plan.Tracks {
+			if i == previewTracks {
+				fmt.Fprintf(&out, "  ... %d more\n", len(plan.Tracks)-previewTracks)
+				break
+			}
+			fmt.Fprintf(&out, "  %d. %s - %s\n", i+1, strings.Join(track.Artists, ", "), track.Name)
+		}
+	}
+
+	_, err := io.WriteString(writer, out.String())
+	return err
+}
+
+// Create creates the planned playlists
+func Create(client restore.Client, plans []Plan, options restore.Options) ([]*restore.Result, error) {
+	var results []*restore.Result
+	for _, plan := range plans {
+		result, err := restore.Restore(client, plan.entry(), options)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
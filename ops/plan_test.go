@@ -0,0 +1,57 @@
+package ops
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/restore"
+)
+
+// Mock Client recording the created playlists
+type mockCreateClient struct {
+	created []string
+}
+
+func (m *mockCreateClient) CurrentUser() (*api.User, error) {
+	return &api.User{Id: "user"}, nil
+}
+
+func (m *mockCreateClient) CreatePlaylist(userId string, details api.PlaylistDetails) (*api.SimplifiedPlaylist, error) {
+	m.created = append(m.created, details.Name)
+	return &api.SimplifiedPlaylist{Id: fmt.Sprintf("new-%d", len(m.created))}, nil
+}
+
+func (m *mockCreateClient) AddItems(playlistId string, uris []string) (string, error) {
+	return "snapshot", nil
+}
+
+func TestPlans(t *testing.T) {
+	plans := []Plan{
+		{Playlist: export.Playlist{Name: "Short"}, Tracks: []export.Track{{Uri: "u", Name: "Song", Artists: []string{"A"}}}},
+		{Playlist: export.Playlist{Name: "Long"}, Tracks: make([]export.Track, 7)},
+	}
+
+	t.Run("it should preview the first tracks of every playlist", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		PrintPlans(buffer, plans)
+
+		expected := "Short (1 tracks)\n  1. A - Song\n" +
+			"Long (7 tracks)\n  1.  - \n  2.  - \n  3.  - \n  4.  - \n  5.  - \n  ... 2 more\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+
+	t.Run("it should create every planned playlist", func(t *testing.T) {
+		client := &mockCreateClient{}
+
+		results, err := Create(client, plans, restore.Options{})
+
+		if err != nil || len(results) != 2 || client.created[1] != "Long" || results[0].Added != 1 {
+			t.Errorf("Unexpected results %+v %v (%v)", results, client.created, err)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package ops
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/enrich"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+	"prisco.dev/spotify-playlist/sequence"
+)
+
+// Reorder moves RangeLength items from RangeStart before the item at
+// InsertBefore, positions refer to the playlist before the move
+type Reorder struct {
+	RangeStart   int
+	InsertBefore int
+	RangeLength  int
+}
+
+type ReorderClient interface {
+	ReorderItems(playlistId string, snapshotId string, rangeStart int, insertBefore int, rangeLength int) (string, error)
+}
+
+type FeatureClient interface {
+	AudioFeatures(ids []string) ([]*api.AudioFeatures, error)
+}
+
+type SortOptions struct {
+	Descending bool
+	DryRun     bool
+	// Features is used when the key references audio features
+	Features FeatureClient
+	Progress io.Writer
+}
+
+// Sort permanently reorders the playlist by the key expression, using the
+// minimal sequence of moves, and returns the moves
+func Sort(client ReorderClient, entry export.PlaylistEntry, key *expr.Expression, options SortOptions) ([]Reorder, error) {
+	if options.Progress == nil {
+		options.Progress = io.Discard
+	}
+
+	envs, err := environments(entry.Tracks, key, options.Features)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := sortOrder(entry, key, envs, options.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	moves := Moves(order)
+	if options.DryRun {
+		fmt.Fprintf(options.Progress, "Would move %d ranges\n", len(moves))
+		return moves, nil
+	}
+
+	snapshotId := entry.Playlist.SnapshotId
+	for i, move := range moves {
+		snapshotId, err = client.ReorderItems(entry.Playlist.Id, snapshotId, move.RangeStart, move.InsertBefore, move.RangeLength)
+		if err != nil {
+			return moves[:i], fmt.Errorf("failed to reorder playlist %s: %w", entry.Playlist.Id, err)
+		}
+		fmt.Fprintf(options.Progress, "Moved %d/%d ranges\n", i+1, len(moves))
+	}
+
+	return moves, nil
+}
+
+// sortOrder returns the current positions of the items in sorted order.
+// Positions missing from the tracks, such as unavailable items, and tracks
+// without key go last.
+func sortOrder(entry export.PlaylistEntry, key *expr.Expression, envs []expr.Env, descending bool) ([]int, error) {
+	size := 0
+	for _, track := range entry.Tracks {
+		size = max(size, track.Position+1)
+	}
+
+	keys := make([]any, size)
+	for i, track := range entry.Tracks {
+		value, err := key.Eval(envs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate %q for track %s: %w", key, track.Id, err)
+		}
+		keys[track.Position] = value
+	}
+
+	order := make([]int, size)
+	for i := range order {
+		order[i] = i
+	}
+
+	var compareError error
+	sort.SliceStable(order, func(i, j int) bool {
+		left, right := keys[order[i]], keys[order[j]]
+		if left == nil || right == nil {
+			return left != nil
+		}
+
+		comparison, err := expr.Compare(left, right)
+		if err != nil {
+			compareError = err
+		}
+		if descending {
+			return comparison > 0
+		}
+		return comparison < 0
+	})
+
+	return order, compareError
+}
+
+// environments exposes the fields of the tracks, looking up the audio
+// features of the tracks when the key references them
+func environments(tracks []export.Track, key *expr.Expression, features FeatureClient) ([]expr.Env, error) {
+	needsFeatures := false
+	for _, name := range key.Identifiers() {
+		needsFeatures = needsFeatures || slices.Contains(export.AudioFeatureColumns, name)
+	}
+
+	if needsFeatures {
+		if features == nil {
+			return nil, fmt.Errorf("%q requires the audio features", key)
+		}
+
+		tracks = slices.Clone(tracks)
+		if err := enrich.AudioFeatures(features, nil, tracks); err != nil {
+			return nil, err
+		}
+	}
+
+	envs := make([]expr.Env, len(tracks))
+	for i, track := range tracks {
+		envs[i] = Fields(track)
+	}
+
+	return envs, nil
+}
+
+// Moves computes a short sequence of moves sorting the playlist, order
+// holds the current positions of the items in the expected order.
+//
+// The longest run of items already in relative order stays in place, every
+// other item is moved right after its predecessor in the expected order,
+// together with the following items when they are already contiguous.
+func Moves(order []int) []Reorder {
+	stays := sequence.LongestIncreasing(order)
+
+	// current is the simulated playlist, holding the original positions
+	current := make([]int, len(order))
+	for i := range current {
+		current[i] = i
+	}
+	indexOf := func(item int) int {
+		return slices.Index(current, item)
+	}
+
+	var moves []Reorder
+	for i := 0; i < len(order); i++ {
+		if stays[i] {
+			continue
+		}
+
+		target := 0
+		if i > 0 {
+			target = indexOf(order[i-1]) + 1
+		}
+
+		start := indexOf(order[i])
+		if start == target {
+			continue
+		}
+
+		length := 1
+		for i+length < len(order) && !stays[i+length] && start+length < len(current) && current[start+length] == order[i+length] {
+			length++
+		}
+
+		moves = append(moves, Reorder{RangeStart: start, InsertBefore: target, RangeLength: length})
+		current = applyMove(current, start, target, length)
+		i += length - 1
+	}
+
+	return moves
+}
+
+func applyMove(items []int, start int, before int, length int) []int {
+	moved := slices.Clone(items[start : start+length])
+	rest := slices.Delete(slices.Clone(items), start, start+length)
+	if before > start {
+		before -= length
+	}
+
+	return slices.Insert(rest, before, moved...)
+}
@@ -0,0 +1,236 @@
+package ops
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/expr"
+	"prisco.dev/spotify-playlist/sequence"
+)
+
+// Mock Client applying the moves to a list of uris
+type mockReorderClient struct {
+	uris      []string
+	snapshots []string
+}
+
+func (m *mockReorderClient) ReorderItems(playlistId string, snapshotId string, rangeStart int, insertBefore int, rangeLength int) (string, error) {
+	m.snapshots = append(m.snapshots, snapshotId)
+
+	positions := make([]int, len(m.uris))
+	for i := range positions {
+		positions[i] = i
+	}
+	moved := applyMove(positions, rangeStart, insertBefore, rangeLength)
+
+	uris := make([]string, len(m.uris))
+	for i, position := range moved {
+		uris[i] = m.uris[position]
+	}
+	m.uris = uris
+
+	return fmt.Sprintf("s%d", len(m.snapshots)+1), nil
+}
+
+// Mock Feature Client
+type mockFeatureClient struct {
+	tempos map[string]float64
+	calls  int
+}
+
+func (m *mockFeatureClient) AudioFeatures(ids []string) ([]*api.AudioFeatures, error) {
+	m.calls++
+
+	features := make([]*api.AudioFeatures, len(ids))
+	for i, id := range ids {
+		if tempo, ok := m.tempos[id]; ok {
+			features[i] = &api.AudioFeatures{Id: id, Tempo: tempo}
+		}
+	}
+	return features, nil
+}
+
+func TestMoves(t *testing.T) {
+	t.Run("it should not move sorted playlists", func(t *testing.T) {
+		if moves := Moves([]int{0, 1, 2, 3}); len(moves) != 0 {
+			t.Errorf("Expected no moves, got %+v", moves)
+		}
+	})
+
+	t.Run("it should move contiguous items as a single range", func(t *testing.T) {
+		// Given the last two items expected first
+		moves := Moves([]int{3, 4, 0, 1, 2})
+
+		// Then a single move should be needed
+		expected := []Reorder{{RangeStart: 3, InsertBefore: 0, RangeLength: 2}}
+		if !reflect.DeepEqual(moves, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, moves)
+		}
+	})
+
+	t.Run("it should move a single item to the end", func(t *testing.T) {
+		moves := Moves([]int{1, 2, 3, 0})
+
+		expected := []Reorder{{RangeStart: 0, InsertBefore: 4, RangeLength: 1}}
+		if !reflect.DeepEqual(moves, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, moves)
+		}
+	})
+
+	t.Run("it should sort random permutations moving only the items out of order", func(t *testing.T) {
+		random := rand.New(rand.NewSource(42))
+		for run := 0; run < 200; run++ {
+			// Given a random expected order
+			order := random.Perm(random.Intn(40) + 1)
+
+			// When applying the moves
+			moves := Moves(order)
+			current := make([]int, len(order))
+			for i := range current {
+				current[i] = i
+			}
+			for _, move := range moves {
+				current = applyMove(current, move.RangeStart, move.InsertBefore, move.RangeLength)
+			}
+
+			// Then the playlist should be in the expected order
+			if !reflect.DeepEqual(current, order) {
+				t.Fatalf("Moves %+v did not sort %v, got %v", moves, order, current)
+			}
+
+			// and no more moves than items outside the longest sorted run should be needed
+			outOfOrder := 0
+			for _, stays := range sequence.LongestIncreasing(order) {
+				if !stays {
+					outOfOrder++
+				}
+			}
+			if len(moves) > outOfOrder {
+				t.Fatalf("Expected at most %d moves for %v, got %d", outOfOrder, order, len(moves))
+			}
+		}
+	})
+}
+
+func TestSort(t *testing.T) {
+	t.Run("it should reorder the playlist by the key", func(t *testing.T) {
+		// Given a playlist
+		entry := createEntry(
+			export.Track{Id: "c", Artists: []string{"Cure"}, ReleaseDate: "1989"},
+			export.Track{Id: "a", Artists: []string{"ABBA"}, ReleaseDate: "1976-08-16"},
+			export.Track{Id: "b", Artists: []string{"blur"}, ReleaseDate: "1994"},
+		)
+		client := &mockReorderClient{uris: uris(entry)}
+
+		// When sorting it by artist
+		key, _ := expr.Parse("artist")
+		_, err := Sort(client, entry, key, SortOptions{})
+
+		// Then the playlist should be sorted case insensitively
+		if err != nil {
+			t.Fatalf("Sort returned an error: %s", err.Error())
+		}
+		if !reflect.DeepEqual(client.uris, []string{"a", "b", "c"}) {
+			t.Errorf("Unexpected order %v", client.uris)
+		}
+
+		// and the snapshots chained
+		if client.snapshots[0] != "s1" {
+			t.Errorf("Expected the first move against the exported snapshot, got %v", client.snapshots)
+		}
+	})
+
+	t.Run("it should sort descending by an expression", func(t *testing.T) {
+		entry := createEntry(
+			export.Track{Id: "old", ReleaseDate: "1969"},
+			export.Track{Id: "new", ReleaseDate: "2020"},
+			export.Track{Id: "unknown"},
+		)
+		client := &mockReorderClient{uris: uris(entry)}
+
+		key, _ := expr.Parse("year - year % 10")
+		Sort(client, entry, key, SortOptions{Descending: true})
+
+		if !reflect.DeepEqual(client.uris, []string{"new", "old", "unknown"}) {
+			t.Errorf("Unexpected order %v", client.uris)
+		}
+	})
+
+	t.Run("it should fetch the audio features to sort by tempo", func(t *testing.T) {
+		entry := createEntry(export.Track{Id: "fast"}, export.Track{Id: "slow"})
+		features := &mockFeatureClient{tempos: map[string]float64{"fast": 170, "slow": 80}}
+		client := &mockReorderClient{uris: uris(entry)}
+
+		key, _ := expr.Parse("tempo")
+		Sort(client, entry, key, SortOptions{Features: features})
+
+		if features.calls != 1 || !reflect.DeepEqual(client.uris, []string{"slow", "fast"}) {
+			t.Errorf("Unexpected order %v after %d calls", client.uris, features.calls)
+		}
+	})
+
+	t.Run("it should only compute the moves in dry run mode", func(t *testing.T) {
+		entry := createEntry(export.Track{Id: "b", Name: "b"}, export.Track{Id: "a", Name: "a"})
+		client := &mockReorderClient{uris: uris(entry)}
+
+		key, _ := expr.Parse("name")
+		moves, err := Sort(client, entry, key, SortOptions{DryRun: true})
+
+		if err != nil || len(moves) != 1 || len(client.snapshots) != 0 {
+			t.Errorf("Unexpected dry run %+v %v (%v)", moves, client.snapshots, err)
+		}
+	})
+
+	t.Run("it should keep the added date order stable", func(t *testing.T) {
+		day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		entry := createEntry(
+			export.Track{Id: "second", AddedAt: day.Add(time.Hour)},
+			export.Track{Id: "first-a", AddedAt: day},
+			export.Track{Id: "first-b", AddedAt: day},
+		)
+		client := &mockReorderClient{uris: uris(entry)}
+
+		key, _ := expr.Parse("added_at")
+		Sort(client, entry, key, SortOptions{})
+
+		if !reflect.DeepEqual(client.uris, []string{"first-a", "first-b", "second"}) {
+			t.Errorf("Unexpected order %v", client.uris)
+		}
+	})
+
+	t.Run("it should return an error when the key cannot be evaluated", func(t *testing.T) {
+		entry := createEntry(export.Track{Id: "a", Name: "a"}, export.Track{Id: "b", Popularity: 1})
+
+		key, _ := expr.Parse("popularity > 0 || name")
+		_, err := Sort(&mockReorderClient{}, entry, key, SortOptions{DryRun: true})
+
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+// Helpers
+func createEntry(tracks ...export.Track) export.PlaylistEntry {
+	entry := export.PlaylistEntry{Playlist: export.Playlist{Id: "p1", SnapshotId: "s1"}}
+	for i, track := range tracks {
+		track.Position = i
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	return entry
+}
+
+func uris(entry export.PlaylistEntry) []string {
+	var result []string
+	for _, track := range entry.Tracks {
+		result = append(result, track.Id)
+	}
+
+	return result
+}
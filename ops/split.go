@@ -0,0 +1,121 @@
+package ops
+
+import (
+	"fmt"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+const (
+	SplitByDecade = "decade"
+	SplitByArtist = "artist"
+	SplitByGenre  = "genre"
+	SplitBySize   = "size"
+)
+
+type ArtistClient interface {
+	Artists(ids []string) ([]*api.Artist, error)
+}
+
+type SplitOptions struct {
+	By string
+	// Size is the number of tracks per playlist when splitting by size
+	Size int
+	// Artists is used to look up the genres when splitting by genre
+	Artists ArtistClient
+}
+
+// Split plans a playlist per group of tracks, named after the group and
+// sorted by first appearance. Tracks without decade, artist or genre are
+// grouped together.
+func Split(entry export.PlaylistEntry, options SplitOptions) ([]Plan, error) {
+	var group func(i int, track export.Track) string
+
+	switch options.By {
+	case SplitByDecade:
+		group = func(_ int, track export.Track) string {
+			if year, ok := Fields(track)["year"].(int); ok {
+				return fmt.Sprintf("%ds", year-year%10)
+			}
+			return "Unknown decade"
+		}
+	case SplitByArtist:
+		group = func(_ int, track export.Track) string {
+			if len(track.Artists) > 0 {
+				return track.Artists[0]
+			}
+			return "Unknown artist"
+		}
+	case SplitByGenre:
+		genres, err := lookupGenres(entry.Tracks, options.Artists)
+		if err != nil {
+			return nil, err
+		}
+		group = func(_ int, track export.Track) string {
+			if len(track.ArtistIds) > 0 && genres[track.ArtistIds[0]] != "" {
+				return genres[track.ArtistIds[0]]
+			}
+			return "Unknown genre"
+		}
+	case SplitBySize:
+		if options.Size < 1 {
+			return nil, fmt.Errorf("invalid split size %d", options.Size)
+		}
+		group = func(i int, _ export.Track) string {
+			return fmt.Sprintf("%d", i/options.Size+1)
+		}
+	default:
+		return nil, fmt.Errorf("unknown split criteria: %s", options.By)
+	}
+
+	var plans []Plan
+	indexes := map[string]int{}
+	for i, track := range entry.Tracks {
+		name := group(i, track)
+		index, ok := indexes[name]
+		if !ok {
+			index = len(plans)
+			indexes[name] = index
+			plans = append(plans, Plan{Playlist: export.Playlist{Name: entry.Playlist.Name + " - " + name}})
+		}
+
+		plans[index].Tracks = append(plans[index].Tracks, track)
+	}
+
+	return plans, nil
+}
+
+// lookupGenres returns the main genre of the first artist of every track
+func lookupGenres(tracks []export.Track, client ArtistClient) (map[string]string, error) {
+	if client == nil {
+		return nil, fmt.Errorf("splitting by genre requires the artists")
+	}
+
+	var ids []string
+	genres := map[string]string{}
+	for _, track := range tracks {
+		if len(track.ArtistIds) == 0 {
+			continue
+		}
+		if _, ok := genres[track.ArtistIds[0]]; !ok {
+			genres[track.ArtistIds[0]] = ""
+			ids = append(ids, track.ArtistIds[0])
+		}
+	}
+
+	for start := 0; start < len(ids); start += api.MaxArtistsPerRequest {
+		artists, err := client.Artists(ids[start:min(start+api.MaxArtistsPerRequest, len(ids))])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the artists: %w", err)
+		}
+
+		for _, artist := range artists {
+			if artist != nil && len(artist.Genres) > 0 {
+				genres[artist.Id] = artist.Genres[0]
+			}
+		}
+	}
+
+	return genres, nil
+}
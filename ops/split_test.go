@@ -0,0 +1,80 @@
+package ops
+
+import (
+	"reflect"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Artist Client
+type mockArtistClient struct {
+	genres map[string][]string
+	calls  int
+}
+
+func (m *mockArtistClient) Artists(ids []string) ([]*api.Artist, error) {
+	m.calls++
+
+	var artists []*api.Artist
+	for _, id := range ids {
+		artist := &api.Artist{Genres: m.genres[id]}
+		artist.Id = id
+		artists = append(artists, artist)
+	}
+	return artists, nil
+}
+
+func TestSplit(t *testing.T) {
+	entry := createEntry(
+		export.Track{Id: "1", Artists: []string{"A"}, ArtistIds: []string{"a"}, ReleaseDate: "1994"},
+		export.Track{Id: "2", Artists: []string{"B"}, ArtistIds: []string{"b"}, ReleaseDate: "2001-01-01"},
+		export.Track{Id: "3", Artists: []string{"A"}, ArtistIds: []string{"a"}, ReleaseDate: "1999"},
+		export.Track{Id: "4"},
+	)
+	entry.Playlist.Name = "Mix"
+
+	tests := []struct {
+		options  SplitOptions
+		expected map[string][]string
+	}{
+		{SplitOptions{By: SplitByDecade}, map[string][]string{
+			"Mix - 1990s": {"1", "3"}, "Mix - 2000s": {"2"}, "Mix - Unknown decade": {"4"},
+		}},
+		{SplitOptions{By: SplitByArtist}, map[string][]string{
+			"Mix - A": {"1", "3"}, "Mix - B": {"2"}, "Mix - Unknown artist": {"4"},
+		}},
+		{SplitOptions{By: SplitBySize, Size: 3}, map[string][]string{
+			"Mix - 1": {"1", "2", "3"}, "Mix - 2": {"4"},
+		}},
+		{SplitOptions{By: SplitByGenre, Artists: &mockArtistClient{genres: map[string][]string{"a": {"grunge", "rock"}}}}, map[string][]string{
+			"Mix - grunge": {"1", "3"}, "Mix - Unknown genre": {"2", "4"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run("it should split by "+test.options.By, func(t *testing.T) {
+			plans, err := Split(entry, test.options)
+			if err != nil {
+				t.Fatalf("Split returned an error: %s", err.Error())
+			}
+
+			result := map[string][]string{}
+			for _, plan := range plans {
+				result[plan.Playlist.Name] = uris(plan.entry())
+			}
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+
+	t.Run("it should refuse unknown criteria and sizes", func(t *testing.T) {
+		for _, options := range []SplitOptions{{By: "mood"}, {By: SplitBySize}, {By: SplitByGenre}} {
+			if _, err := Split(entry, options); err == nil {
+				t.Errorf("Expected an error for %+v", options)
+			}
+		}
+	})
+}
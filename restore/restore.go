@@ -0,0 +1,96 @@
+package restore
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+type Client interface {
+	CurrentUser() (*api.User, error)
+	CreatePlaylist(userId string, details api.PlaylistDetails) (*api.SimplifiedPlaylist, error)
+	AddItems(playlistId string, uris []string) (string, error)
+}
+
+type Options struct {
+	// Name overrides the name of the saved playlist when not empty
+	Name   string
+	DryRun bool
+	// Progress receives a line per batch of added tracks
+	Progress io.Writer
+}
+
+type Result struct {
+	PlaylistId string
+	Added      int
+	// Skipped holds the tracks without an uri, such as local files
+	Skipped []export.Track
+}
+
+// Restore recreates the saved playlist, in dry run mode no playlist is
+// created but the result reports what would be restored
+func Restore(client Client, entry export.PlaylistEntry, options Options) (*Result, error) {
+	if options.Progress == nil {
+		options.Progress = io.Discard
+	}
+
+	tracks := append([]export.Track{}, entry.Tracks...)
+	sort.SliceStable(tracks, func(i, j int) bool {
+		return tracks[i].Position < tracks[j].Position
+	})
+
+	result := &Result{}
+	var uris []string
+	for _, track := range tracks {
+		// Local files cannot be added through the API
+		if track.Uri == "" || track.Type == export.TypeLocal {
+			result.Skipped = append(result.Skipped, track)
+			continue
+		}
+		uris = append(uris, track.Uri)
+	}
+
+	details := api.PlaylistDetails{
+		Name:        entry.Playlist.Name,
+		Description: entry.Playlist.Description,
+		Public:      entry.Playlist.Public,
+		// Collaborative playlists cannot be public
+		Collaborative: entry.Playlist.Collaborative && !entry.Playlist.Public,
+	}
+	if options.Name != "" {
+		details.Name = options.Name
+	}
+
+	if options.DryRun {
+		fmt.Fprintf(options.Progress, "Would create playlist %q with %d tracks\n", details.Name, len(uris))
+		result.Added = len(uris)
+		return result, nil
+	}
+
+	user, err := client.CurrentUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the current user: %w", err)
+	}
+
+	playlist, err := client.CreatePlaylist(user.Id, details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the playlist: %w", err)
+	}
+	result.PlaylistId = playlist.Id
+	fmt.Fprintf(options.Progress, "Created playlist %q (%s)\n", details.Name, playlist.Id)
+
+	for start := 0; start < len(uris); start += api.MaxItemsPerRequest {
+		end := min(start+api.MaxItemsPerRequest, len(uris))
+		if _, err := client.AddItems(playlist.Id, uris[start:end]); err != nil {
+			return result, fmt.Errorf("failed to add tracks %d-%d: %w", start+1, end, err)
+		}
+
+		result.Added = end
+		fmt.Fprintf(options.Progress, "Added %d/%d tracks\n", end, len(uris))
+	}
+
+	return result, nil
+}
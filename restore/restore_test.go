@@ -0,0 +1,122 @@
+package restore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Client recording the calls
+type mockClient struct {
+	created *api.PlaylistDetails
+	batches [][]string
+
+	addError error
+}
+
+func (m *mockClient) CurrentUser() (*api.User, error) {
+	return &api.User{Id: "user-id"}, nil
+}
+
+func (m *mockClient) CreatePlaylist(userId string, details api.PlaylistDetails) (*api.SimplifiedPlaylist, error) {
+	if userId != "user-id" {
+		return nil, errors.New("unexpected user")
+	}
+
+	m.created = &details
+	return &api.SimplifiedPlaylist{Id: "new-id"}, nil
+}
+
+func (m *mockClient) AddItems(playlistId string, uris []string) (string, error) {
+	if m.addError != nil {
+		return "", m.addError
+	}
+
+	m.batches = append(m.batches, uris)
+	return "snapshot", nil
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("it should recreate the playlist adding tracks in batches of 100", func(t *testing.T) {
+		// Given a saved playlist with 250 tracks in reverse position order and two local files
+		entry := createEntry(250)
+		entry.Tracks = append(entry.Tracks,
+			export.Track{Position: 250, Name: "local file"},
+			export.Track{Position: 251, Type: export.TypeLocal, Uri: "spotify:local:a:b:c:1"},
+		)
+		client := &mockClient{}
+
+		// When restoring it
+		progress := &bytes.Buffer{}
+		result, err := Restore(client, entry, Options{Progress: progress})
+
+		// Then the playlist should be created with the saved details
+		if err != nil {
+			t.Fatalf("Restore returned an error: %s", err.Error())
+		}
+		expected := api.PlaylistDetails{Name: "Saved", Description: "Description", Collaborative: true}
+		if client.created == nil || *client.created != expected {
+			t.Errorf("Expected %+v, got %+v", expected, client.created)
+		}
+
+		// and the tracks added in order in 3 batches
+		if len(client.batches) != 3 || len(client.batches[0]) != 100 || len(client.batches[2]) != 50 {
+			t.Fatalf("Unexpected batches %v", client.batches)
+		}
+		if client.batches[0][0] != "spotify:track:0" || client.batches[2][49] != "spotify:track:249" {
+			t.Errorf("Tracks were not added in order")
+		}
+
+		// and the local files skipped
+		if result.PlaylistId != "new-id" || result.Added != 250 || len(result.Skipped) != 2 {
+			t.Errorf("Unexpected result %+v", result)
+		}
+
+		// and the progress reported
+		if !bytes.Contains(progress.Bytes(), []byte("Added 200/250 tracks\n")) {
+			t.Errorf("Unexpected progress %s", progress.String())
+		}
+	})
+
+	t.Run("it should not call the api in dry run mode", func(t *testing.T) {
+		client := &mockClient{}
+
+		result, err := Restore(client, createEntry(10), Options{DryRun: true, Name: "Renamed"})
+
+		if err != nil || client.created != nil || len(client.batches) != 0 {
+			t.Errorf("Expected no call in dry run, got %+v %v (%v)", client.created, client.batches, err)
+		}
+		if result.Added != 10 {
+			t.Errorf("Expected 10 tracks to be reported, got %d", result.Added)
+		}
+	})
+
+	t.Run("it should report how far the restore went on failure", func(t *testing.T) {
+		client := &mockClient{addError: errors.New("mock error")}
+
+		result, err := Restore(client, createEntry(10), Options{})
+
+		if err == nil || err.Error() != "failed to add tracks 1-10: mock error" || result.PlaylistId != "new-id" {
+			t.Errorf("Unexpected result %+v (%v)", result, err)
+		}
+	})
+}
+
+// Helpers
+func createEntry(tracks int) export.PlaylistEntry {
+	entry := export.PlaylistEntry{Playlist: export.Playlist{
+		Name:          "Saved",
+		Description:   "Description",
+		Collaborative: true,
+	}}
+
+	for i := tracks - 1; i >= 0; i-- {
+		entry.Tracks = append(entry.Tracks, export.Track{Position: i, Uri: fmt.Sprintf("spotify:track:%d", i)})
+	}
+
+	return entry
+}
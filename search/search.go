@@ -0,0 +1,195 @@
+package search
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+	"prisco.dev/spotify-playlist/library"
+)
+
+// DefaultLimit is the number of results per type
+const DefaultLimit = 20
+
+type Searcher interface {
+	Search(query api.Query, options api.SearchOptions) (*api.SearchResults, error)
+}
+
+type Options struct {
+	// Types of the results, tracks by default
+	Types  []string
+	Market string
+	// Limit is the number of results per type, fetched over as many pages
+	// as needed up to api.MaxSearchOffset
+	Limit int
+}
+
+// Result is a search result of any type
+type Result struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+	Uri  string `json:"uri"`
+	Name string `json:"name"`
+	// Detail is the artists of tracks and albums, the owner of playlists or
+	// the publisher of shows and episodes
+	Detail string `json:"detail"`
+}
+
+type Results struct {
+	Query string   `json:"query"`
+	Items []Result `json:"items"`
+	// Tracks are the tracks and episodes found, as exported
+	Tracks []export.Track `json:"-"`
+}
+
+// Search returns the results of the query, grouped by type in the order of
+// the requested types
+func Search(searcher Searcher, query api.Query, options Options) (*Results, error) {
+	types := options.Types
+	if len(types) == 0 {
+		types = []string{api.SearchTrack}
+	}
+	if options.Limit <= 0 {
+		options.Limit = DefaultLimit
+	}
+
+	byType := map[string][]Result{}
+	var tracks, episodes []export.Track
+	pending := types
+	for offset := 0; len(pending) > 0 && offset < api.MaxSearchOffset; {
+		// Pages may come back short of the unavailable items, so the offset
+		// follows the requested limit rather than the results
+		limit := min(options.Limit-offset, api.MaxSearchLimit)
+		if limit <= 0 {
+			break
+		}
+
+		results, err := searcher.Search(query, api.SearchOptions{Types: pending, Market: options.Market, Limit: limit, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %q: %w", query, err)
+		}
+
+		var next []string
+		for _, kind := range pending {
+			found, more := collect(results, kind, &tracks, &episodes)
+			byType[kind] = append(byType[kind], found...)
+			if more && len(byType[kind]) < options.Limit {
+				next = append(next, kind)
+			}
+		}
+		pending = next
+		offset += limit
+	}
+
+	combined := &Results{Query: query.String(), Items: []Result{}}
+	for _, kind := range types {
+		combined.Items = append(combined.Items, byType[kind]...)
+	}
+	for i, track := range append(tracks, episodes...) {
+		track.Position = i
+		combined.Tracks = append(combined.Tracks, track)
+	}
+
+	return combined, nil
+}
+
+// collect returns the results of a type in the page, converting tracks and
+// episodes for the exporters, and whether more results follow
+func collect(results *api.SearchResults, kind string, tracks *[]export.Track, episodes *[]export.Track) ([]Result, bool) {
+	var found []Result
+	switch kind {
+	case api.SearchTrack:
+		for _, track := range results.Tracks.Items {
+			converted := library.ToTrack(0, track)
+			*tracks = append(*tracks, converted)
+			found = append(found, Result{kind, track.Id, track.Uri, track.Name, strings.Join(converted.Artists, ", ")})
+		}
+		return found, results.Tracks.Next != ""
+	case api.SearchAlbum:
+		for _, album := range results.Albums.Items {
+			artists := make([]string, 0, len(album.Artists))
+			for _, artist := range album.Artists {
+				artists = append(artists, artist.Name)
+			}
+			found = append(found, Result{kind, album.Id, album.Uri, album.Name, strings.Join(artists, ", ")})
+		}
+		return found, results.Albums.Next != ""
+	case api.SearchArtist:
+		for _, artist := range results.Artists.Items {
+			found = append(found, Result{kind, artist.Id, artist.Uri, artist.Name, strings.Join(artist.Genres, ", ")})
+		}
+		return found, results.Artists.Next != ""
+	case api.SearchPlaylist:
+		for _, playlist := range results.Playlists.Items {
+			if playlist != nil {
+				found = append(found, Result{kind, playlist.Id, playlist.Uri, playlist.Name, playlist.Owner.Id})
+			}
+		}
+		return found, results.Playlists.Next != ""
+	case api.SearchShow:
+		for _, show := range results.Shows.Items {
+			if show != nil {
+				found = append(found, Result{kind, show.Id, show.Uri, show.Name, show.Publisher})
+			}
+		}
+		return found, results.Shows.Next != ""
+	case api.SearchEpisode:
+		for _, episode := range results.Episodes.Items {
+			if episode != nil {
+				converted := library.ToEpisodeTrack(0, *episode)
+				*episodes = append(*episodes, converted)
+				found = append(found, Result{kind, episode.Id, episode.Uri, episode.Name, strings.Join(converted.Artists, ", ")})
+			}
+		}
+		return found, results.Episodes.Next != ""
+	}
+
+	return nil, false
+}
+
+// Entry returns the tracks and episodes found as a playlist of the name
+func (r *Results) Entry(name string) export.PlaylistEntry {
+	return export.PlaylistEntry{
+		Playlist: export.Playlist{Id: "search", Name: name, Description: r.Query, TrackCount: len(r.Tracks)},
+		Tracks:   r.Tracks,
+	}
+}
+
+// Source returns the tracks and episodes found as a playlist source for the
+// exporters
+func (r *Results) Source(name string) export.PlaylistSource {
+	return entrySource{r.Entry(name)}
+}
+
+type entrySource struct {
+	entry export.PlaylistEntry
+}
+
+func (e entrySource) Playlists() ([]export.Playlist, error) {
+	return []export.Playlist{e.entry.Playlist}, nil
+}
+
+func (e entrySource) Tracks(playlist export.Playlist, handle func(export.Track) error) error {
+	for _, track := range e.entry.Tracks {
+		if err := handle(track); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrintResults writes a row per result under an upper case header
+func PrintResults(writer io.Writer, results *Results) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(table, "TYPE\tNAME\tDETAIL\tURI")
+	for _, result := range results.Items {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", result.Type, result.Name, result.Detail, result.Uri)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"prisco.dev/spotify-playlist/client/api"
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Searcher returning full pages of tracks, pages of playlists starting
+// with an unavailable one and a single page of shows
+type mockSearcher struct {
+	calls []string
+}
+
+func (m *mockSearcher) Search(query api.Query, options api.SearchOptions) (*api.SearchResults, error) {
+	m.calls = append(m.calls, fmt.Sprintf("%s %d+%d", strings.Join(options.Types, ","), options.Offset, options.Limit))
+
+	results := &api.SearchResults{}
+	for _, kind := range options.Types {
+		switch kind {
+		case api.SearchTrack:
+			for i := 0; i < options.Limit; i++ {
+				id := fmt.Sprintf("t%d", options.Offset+i)
+				results.Tracks.Items = append(results.Tracks.Items, api.Track{Id: id, Uri: "spotify:track:" + id})
+			}
+			results.Tracks.Next = "next"
+		case api.SearchPlaylist:
+			results.Playlists.Items = []*api.SimplifiedPlaylist{nil}
+			for i := 1; i < options.Limit; i++ {
+				results.Playlists.Items = append(results.Playlists.Items, &api.SimplifiedPlaylist{Id: fmt.Sprintf("p%d", options.Offset+i)})
+			}
+			results.Playlists.Next = "next"
+		case api.SearchShow:
+			results.Shows.Items = []*api.SimplifiedShow{nil, {Id: "s1", Name: "Show", Publisher: "Publisher"}}
+		case api.SearchEpisode:
+			results.Episodes.Items = []*api.Episode{{Id: "e1", Uri: "spotify:episode:e1"}}
+		}
+	}
+	return results, nil
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("it should fetch the pages until the limit of every type", func(t *testing.T) {
+		// Given a searcher with many tracks and a single page of shows
+		searcher := &mockSearcher{}
+
+		// When searching 120 results of both types
+		results, err := Search(searcher, api.Query{Keywords: "news"}, Options{Types: []string{api.SearchShow, api.SearchTrack}, Limit: 120})
+
+		// Then the shows should only be requested on the first page
+		expected := []string{"show,track 0+50", "track 50+50", "track 100+20"}
+		if err != nil || !reflect.DeepEqual(searcher.calls, expected) {
+			t.Fatalf("Expected %v, got %v (%v)", expected, searcher.calls, err)
+		}
+
+		// and the results grouped by type, without the unavailable show
+		if len(results.Items) != 121 || results.Items[0].Detail != "Publisher" || results.Items[120].Id != "t119" {
+			t.Errorf("Unexpected results %d %+v", len(results.Items), results.Items[0])
+		}
+		if results.Query != "news" || len(results.Tracks) != 120 || results.Tracks[119].Position != 119 {
+			t.Errorf("Unexpected tracks %d", len(results.Tracks))
+		}
+	})
+
+	t.Run("it should request the next results after a short page", func(t *testing.T) {
+		// Given a searcher returning pages with an unavailable playlist
+		searcher := &mockSearcher{}
+
+		// When searching 60 playlists
+		results, err := Search(searcher, api.Query{Keywords: "rock"}, Options{Types: []string{api.SearchPlaylist}, Limit: 60})
+
+		// Then the second page should follow the first one
+		expected := []string{"playlist 0+50", "playlist 50+10"}
+		if err != nil || !reflect.DeepEqual(searcher.calls, expected) {
+			t.Fatalf("Expected %v, got %v (%v)", expected, searcher.calls, err)
+		}
+		if len(results.Items) != 58 || results.Items[49].Id != "p51" {
+			t.Errorf("Unexpected results %d %+v", len(results.Items), results.Items)
+		}
+	})
+
+	t.Run("it should stop at the limit after a short page", func(t *testing.T) {
+		searcher := &mockSearcher{}
+
+		Search(searcher, api.Query{Keywords: "rock"}, Options{Types: []string{api.SearchPlaylist}, Limit: 20})
+
+		if expected := []string{"playlist 0+20"}; !reflect.DeepEqual(searcher.calls, expected) {
+			t.Errorf("Expected %v, got %v", expected, searcher.calls)
+		}
+	})
+
+	t.Run("it should export the tracks and episodes as a playlist", func(t *testing.T) {
+		results, _ := Search(&mockSearcher{}, api.Query{Artist: "Air"}, Options{Types: []string{api.SearchEpisode, api.SearchTrack}, Limit: 2})
+
+		buffer := &bytes.Buffer{}
+		err := export.Export(results.Source("Found"), mustFormat(t, "m3u8"), buffer, export.Options{})
+
+		expected := "#EXTM3U\n#PLAYLIST:Found\n"
+		if err != nil || !strings.HasPrefix(buffer.String(), expected) || !strings.HasSuffix(buffer.String(), "spotify:episode:e1\n") {
+			t.Errorf("Unexpected export %s (%v)", buffer.String(), err)
+		}
+	})
+}
+
+func TestPrintResults(t *testing.T) {
+	t.Run("it should print a row per result", func(t *testing.T) {
+		results := &Results{Items: []Result{{Type: "track", Name: "Song", Detail: "Artist", Uri: "spotify:track:1"}}}
+
+		buffer := &bytes.Buffer{}
+		PrintResults(buffer, results)
+
+		expected := "TYPE   NAME  DETAIL  URI\ntrack  Song  Artist  spotify:track:1\n"
+		if buffer.String() != expected {
+			t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+		}
+	})
+}
+
+// Helpers
+func mustFormat(t *testing.T, name string) export.Format {
+	format, err := export.ParseFormat(name)
+	if err != nil {
+		t.Fatalf("ParseFormat returned an error: %s", err.Error())
+	}
+
+	return format
+}
@@ -0,0 +1,35 @@
+package sequence
+
+import "sort"
+
+// LongestIncreasing flags the values belonging to a longest strictly
+// increasing subsequence
+func LongestIncreasing(values []int) []bool {
+	// tails[k] is the index of the smallest tail of an increasing subsequence of length k+1
+	var tails []int
+	previous := make([]int, len(values))
+	for i, value := range values {
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= value })
+		if k > 0 {
+			previous[i] = tails[k-1]
+		} else {
+			previous[i] = -1
+		}
+
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	result := make([]bool, len(values))
+	if len(tails) == 0 {
+		return result
+	}
+	for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+		result[i] = true
+	}
+
+	return result
+}
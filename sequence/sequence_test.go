@@ -0,0 +1,26 @@
+package sequence
+
+import "testing"
+
+func TestLongestIncreasing(t *testing.T) {
+	tests := []struct {
+		values   []int
+		expected []bool
+	}{
+		{[]int{}, []bool{}},
+		{[]int{0, 1, 2}, []bool{true, true, true}},
+		{[]int{2, 1, 0}, []bool{false, false, true}},
+		{[]int{1, 0, 2, 3}, []bool{false, true, true, true}},
+		{[]int{3, 0, 1, 2}, []bool{false, true, true, true}},
+	}
+
+	for _, test := range tests {
+		result := LongestIncreasing(test.values)
+		for i := range test.expected {
+			if result[i] != test.expected[i] {
+				t.Errorf("Expected %v for %v, got %v", test.expected, test.values, result)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// StateFile is stored in the export directory and remembers the snapshot of
+// every exported playlist
+const StateFile = ".snapshots.json"
+
+type State struct {
+	// Playlists maps a playlist id to its last export
+	Playlists map[string]Entry `json:"playlists"`
+}
+
+type Entry struct {
+	SnapshotId string `json:"snapshot_id"`
+	File       string `json:"file"`
+}
+
+// LoadState reads the state of the directory, a missing state is empty
+func LoadState(directory string) (*State, error) {
+	state := &State{Playlists: map[string]Entry{}}
+
+	content, err := os.ReadFile(filepath.Join(directory, StateFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot state: %w", err)
+	}
+
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot state: %w", err)
+	}
+	if state.Playlists == nil {
+		state.Playlists = map[string]Entry{}
+	}
+
+	return state, nil
+}
+
+func (s *State) Save(directory string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot state: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(directory, StateFile), append(content, '\n'), 0o644)
+}
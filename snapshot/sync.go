@@ -0,0 +1,102 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+type Report struct {
+	Added     []export.Playlist
+	Modified  []export.Playlist
+	Unchanged []export.Playlist
+	// Removed holds the ids of the playlists which are no longer in the library
+	Removed []string
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf(
+		"%d added, %d modified, %d removed, %d unchanged",
+		len(r.Added), len(r.Modified), len(r.Removed), len(r.Unchanged),
+	)
+}
+
+// Sync exports to the directory only the playlists whose snapshot changed
+// since the last sync, files of removed playlists are deleted
+func Sync(source export.PlaylistSource, format export.Format, directory string, options export.Options) (*Report, error) {
+	state, err := LoadState(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists, err := source.Playlists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	report := &Report{}
+	var changed []export.Playlist
+	seen := map[string]bool{}
+	for _, playlist := range playlists {
+		seen[playlist.Id] = true
+
+		previous, ok := state.Playlists[playlist.Id]
+		file := export.FileName(playlist, format)
+		switch {
+		case !ok:
+			report.Added = append(report.Added, playlist)
+		case previous.SnapshotId != playlist.SnapshotId || previous.File != file || !fileExists(filepath.Join(directory, file)):
+			report.Modified = append(report.Modified, playlist)
+		default:
+			report.Unchanged = append(report.Unchanged, playlist)
+			continue
+		}
+
+		changed = append(changed, playlist)
+	}
+
+	err = export.ExportToDirectory(fixedSource{source, changed}, format, directory, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, entry := range state.Playlists {
+		if !seen[id] {
+			report.Removed = append(report.Removed, id)
+			delete(state.Playlists, id)
+			os.Remove(filepath.Join(directory, entry.File))
+		}
+	}
+
+	sort.Strings(report.Removed)
+
+	for _, playlist := range changed {
+		// A renamed playlist leaves its previous export behind
+		file := export.FileName(playlist, format)
+		if previous, ok := state.Playlists[playlist.Id]; ok && previous.File != file {
+			os.Remove(filepath.Join(directory, previous.File))
+		}
+
+		state.Playlists[playlist.Id] = Entry{SnapshotId: playlist.SnapshotId, File: file}
+	}
+
+	return report, state.Save(directory)
+}
+
+// fixedSource restricts a source to the given playlists
+type fixedSource struct {
+	export.PlaylistSource
+	playlists []export.Playlist
+}
+
+func (f fixedSource) Playlists() ([]export.Playlist, error) {
+	return f.playlists, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,111 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+// Mock Playlist Source recording the exported playlists
+type mockSource struct {
+	playlists []export.Playlist
+	fetched   []string
+}
+
+func (m *mockSource) Playlists() ([]export.Playlist, error) {
+	return m.playlists, nil
+}
+
+func (m *mockSource) Tracks(playlist export.Playlist, handle func(export.Track) error) error {
+	m.fetched = append(m.fetched, playlist.Id)
+	return handle(export.Track{Id: "track-of-" + playlist.Id})
+}
+
+func TestSync(t *testing.T) {
+	format, _ := export.ParseFormat("json")
+
+	t.Run("it should export every playlist on the first sync", func(t *testing.T) {
+		// Given an empty directory
+		directory := t.TempDir()
+		source := &mockSource{playlists: []export.Playlist{{Id: "p1", SnapshotId: "s1"}, {Id: "p2", SnapshotId: "s1"}}}
+
+		// When syncing
+		report, err := Sync(source, format, directory, export.Options{})
+
+		// Then every playlist should be added
+		if err != nil {
+			t.Fatalf("Sync returned an error: %s", err.Error())
+		}
+		if len(report.Added) != 2 || len(source.fetched) != 2 {
+			t.Errorf("Expected 2 added playlists, got %s", report)
+		}
+
+		// and the state saved
+		state, _ := LoadState(directory)
+		if state.Playlists["p1"].SnapshotId != "s1" || state.Playlists["p2"].File != "_p2.json" {
+			t.Errorf("Unexpected state %+v", state)
+		}
+	})
+
+	t.Run("it should only refetch the modified playlists", func(t *testing.T) {
+		// Given a synced directory
+		directory := t.TempDir()
+		Sync(&mockSource{playlists: []export.Playlist{
+			{Id: "p1", SnapshotId: "s1"},
+			{Id: "p2", SnapshotId: "s1"},
+			{Id: "p3", Name: "Old", SnapshotId: "s1"},
+			{Id: "p4", SnapshotId: "s1"},
+		}}, format, directory, export.Options{})
+
+		// When syncing a library where p2 changed, p3 was renamed, p4 was removed and p5 added
+		source := &mockSource{playlists: []export.Playlist{
+			{Id: "p1", SnapshotId: "s1"},
+			{Id: "p2", SnapshotId: "s2"},
+			{Id: "p3", Name: "New", SnapshotId: "s2"},
+			{Id: "p5", SnapshotId: "s1"},
+		}}
+		report, err := Sync(source, format, directory, export.Options{})
+
+		// Then only the modified and added playlists should be fetched
+		if err != nil {
+			t.Fatalf("Sync returned an error: %s", err.Error())
+		}
+		if len(source.fetched) != 3 || source.fetched[0] != "p2" || source.fetched[1] != "p3" || source.fetched[2] != "p5" {
+			t.Errorf("Unexpected fetched playlists %v", source.fetched)
+		}
+
+		// and the counts reported
+		if report.String() != "1 added, 2 modified, 1 removed, 1 unchanged" {
+			t.Errorf("Unexpected report %s", report)
+		}
+
+		// and the stale files removed
+		for _, file := range []string{"_p4.json", "Old_p3.json"} {
+			if _, err := os.Stat(filepath.Join(directory, file)); err == nil {
+				t.Errorf("Expected %s to be removed", file)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(directory, "New_p3.json")); err != nil {
+			t.Errorf("Expected the renamed playlist to be exported")
+		}
+	})
+
+	t.Run("it should refetch unchanged playlists whose export is missing", func(t *testing.T) {
+		// Given a synced directory
+		directory := t.TempDir()
+		playlists := []export.Playlist{{Id: "p1", SnapshotId: "s1"}}
+		Sync(&mockSource{playlists: playlists}, format, directory, export.Options{})
+
+		// When the export is deleted
+		os.Remove(filepath.Join(directory, "_p1.json"))
+		source := &mockSource{playlists: playlists}
+		report, _ := Sync(source, format, directory, export.Options{})
+
+		// Then the playlist should be exported again
+		if len(report.Modified) != 1 || len(source.fetched) != 1 {
+			t.Errorf("Expected the playlist to be exported again, got %s", report)
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	OutputText  = "text"
+	OutputTable = "table"
+	OutputJson  = "json"
+	OutputHtml  = "html"
+)
+
+// Render writes the statistics as terminal tables, json or a standalone
+// html report titled after the scope, such as a playlist name
+func Render(writer io.Writer, stats *Stats, title string, output string) error {
+	switch output {
+	case OutputText, OutputTable:
+		return renderText(writer, stats)
+	case OutputJson:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case OutputHtml:
+		return renderHtml(writer, stats, title)
+	}
+
+	return fmt.Errorf("unknown stats output: %s", output)
+}
+
+// section is a titled table of the report
+type section struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+	// Shares are the widths of the bars of the rows in the html report,
+	// between 0 and 100
+	Shares []float64
+}
+
+func sections(stats *Stats) []section {
+	summary := section{
+		Title:  "Summary",
+		Header: []string{"", ""},
+		Rows: [][]string{
+			{"Playlists", strconv.Itoa(stats.Playlists)},
+			{"Tracks", fmt.Sprintf("%d (%d unique)", stats.Tracks, stats.UniqueTracks)},
+			{"Artists", strconv.Itoa(stats.Artists)},
+			{"Albums", strconv.Itoa(stats.Albums)},
+			{"Duration", formatDuration(stats.DurationMs)},
+			{"Explicit", fmt.Sprintf("%d (%.0f%%)", stats.Explicit, stats.ExplicitRatio*100)},
+		},
+	}
+
+	result := []section{
+		summary,
+		countSection("Top artists", "Artist", stats.TopArtists),
+		countSection("Top genres", "Genre", stats.TopGenres),
+		countSection("Decades", "Decade", stats.Decades),
+	}
+	if features := stats.Features; features != nil {
+		result = append(result, section{
+			Title:  fmt.Sprintf("Audio features (%d tracks)", features.Tracks),
+			Header: []string{"Feature", "Average"},
+			Rows: [][]string{
+				{"danceability", formatFloat(features.Danceability)},
+				{"energy", formatFloat(features.Energy)},
+				{"valence", formatFloat(features.Valence)},
+				{"tempo", formatFloat(features.Tempo)},
+				{"loudness", formatFloat(features.Loudness)},
+			},
+		})
+	}
+	result = append(result, countSection("Top adders", "User", stats.TopAdders))
+
+	// Rankings without entries, such as genres without the full artists, are left out
+	nonEmpty := result[:0]
+	for _, section := range result {
+		if len(section.Rows) > 0 {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+
+	return nonEmpty
+}
+
+func countSection(title string, name string, counts []Count) section {
+	section := section{Title: title, Header: []string{name, "Tracks"}}
+
+	highest := 0
+	for _, count := range counts {
+		highest = max(highest, count.Count)
+	}
+	for _, count := range counts {
+		section.Rows = append(section.Rows, []string{count.Name, strconv.Itoa(count.Count)})
+		section.Shares = append(section.Shares, float64(count.Count)*100/float64(highest))
+	}
+
+	return section
+}
+
+func renderText(writer io.Writer, stats *Stats) error {
+	table := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	for i, section := range sections(stats) {
+		if i > 0 {
+			fmt.Fprintln(table)
+		}
+
+		header := section.Header
+		if header[0] == "" {
+			header = []string{section.Title}
+		}
+		fmt.Fprintln(table, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range section.Rows {
+			fmt.Fprintln(table, strings.Join(row, "\t"))
+		}
+	}
+
+	return table.Flush()
+}
+
+var htmlTemplate = template.Must(template.New("stats").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 48rem; color: #191414; }
+h1 { color: #1db954; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; }
+th, td { text-align: left; padding: 0.25rem 0.5rem; border-bottom: 1px solid #eee; }
+td.bar { width: 50%; }
+td.bar div { background: #1db954; height: 0.75rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+{{if index .Header 0}}<tr>{{range .Header}}<th>{{.}}</th>{{end}}{{if .Shares}}<th></th>{{end}}</tr>
+{{end}}{{$shares := .Shares}}{{range $i, $row := .Rows}}<tr>{{range $row}}<td>{{.}}</td>{{end}}{{if $shares}}<td class="bar"><div style="width: {{printf "%.1f" (index $shares $i)}}%"></div></td>{{end}}</tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+func renderHtml(writer io.Writer, stats *Stats, title string) error {
+	return htmlTemplate.Execute(writer, struct {
+		Title    string
+		Sections []section
+	}{title, sections(stats)})
+}
+
+// formatDuration formats the duration in days, hours and minutes
+func formatDuration(ms int64) string {
+	duration := time.Duration(ms) * time.Millisecond
+	days := int(duration.Hours()) / 24
+	hours := int(duration.Hours()) % 24
+	minutes := int(duration.Minutes()) % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
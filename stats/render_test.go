@@ -0,0 +1,42 @@
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	stats := &Stats{
+		Playlists: 1, Tracks: 4, UniqueTracks: 3, Artists: 2, Albums: 2, DurationMs: 90061000, Explicit: 1, ExplicitRatio: 0.25,
+		TopArtists: []Count{{"Artist", 3}, {"<Other>", 1}},
+		Decades:    []Count{{"1990s", 4}},
+	}
+
+	t.Run("it should render the sections as tables", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		Render(buffer, stats, "Library", OutputText)
+
+		for _, expected := range []string{"Tracks     4 (3 unique)\n", "Duration   1d 1h 1m\n", "Explicit   1 (25%)\n", "ARTIST   TRACKS\nArtist   3\n"} {
+			if !strings.Contains(buffer.String(), expected) {
+				t.Errorf("Expected %q in\n%s", expected, buffer.String())
+			}
+		}
+
+		// Rankings without entries are left out
+		if strings.Contains(buffer.String(), "GENRE") {
+			t.Errorf("Unexpected empty genres in\n%s", buffer.String())
+		}
+	})
+
+	t.Run("it should render a standalone html report", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		Render(buffer, stats, "Library", OutputHtml)
+
+		for _, expected := range []string{"<title>Library</title>", "<td>&lt;Other&gt;</td>", `<div style="width: 33.3%">`} {
+			if !strings.Contains(buffer.String(), expected) {
+				t.Errorf("Expected %q in\n%s", expected, buffer.String())
+			}
+		}
+	})
+}
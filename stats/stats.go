@@ -0,0 +1,194 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+// DefaultTop is the number of entries of the rankings
+const DefaultTop = 10
+
+// Count is an entry of a ranking or of a distribution
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Features are the audio features averaged over the tracks which have them
+type Features struct {
+	Tracks       int     `json:"tracks"`
+	Danceability float64 `json:"danceability"`
+	Energy       float64 `json:"energy"`
+	Valence      float64 `json:"valence"`
+	Tempo        float64 `json:"tempo"`
+	Loudness     float64 `json:"loudness"`
+}
+
+type Stats struct {
+	Playlists    int   `json:"playlists"`
+	Tracks       int   `json:"tracks"`
+	UniqueTracks int   `json:"unique_tracks"`
+	Artists      int   `json:"artists"`
+	Albums       int   `json:"albums"`
+	DurationMs   int64 `json:"duration_ms"`
+	Explicit     int   `json:"explicit"`
+	// ExplicitRatio is the share of explicit tracks, between 0 and 1
+	ExplicitRatio float64 `json:"explicit_ratio"`
+	TopArtists    []Count `json:"top_artists"`
+	// TopGenres requires the full artists, it counts every genre of every
+	// artist of the tracks
+	TopGenres []Count `json:"top_genres"`
+	// Decades is the distribution of the release dates, by decade
+	Decades []Count `json:"decades"`
+	// Features requires the audio features, it is nil without them
+	Features *Features `json:"audio_features"`
+	// TopAdders ranks who added the tracks of the collaborative playlists
+	TopAdders []Count `json:"top_adders"`
+}
+
+// Collector is an exporter computing the statistics of the exported tracks
+type Collector struct {
+	playlist export.Playlist
+	stats    Stats
+	uris     map[string]bool
+	artists  map[string]int
+	names    map[string]string
+	albums   map[string]bool
+	genres   map[string]int
+	decades  map[string]int
+	adders   map[string]int
+	features Features
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		uris:    map[string]bool{},
+		artists: map[string]int{},
+		names:   map[string]string{},
+		albums:  map[string]bool{},
+		genres:  map[string]int{},
+		decades: map[string]int{},
+		adders:  map[string]int{},
+	}
+}
+
+func (c *Collector) BeginPlaylist(playlist export.Playlist) error {
+	c.playlist = playlist
+	c.stats.Playlists++
+	return nil
+}
+
+func (c *Collector) WriteTrack(track export.Track) error {
+	c.stats.Tracks++
+	c.stats.DurationMs += int64(track.DurationMs)
+	if track.Explicit {
+		c.stats.Explicit++
+	}
+	if track.Uri != "" {
+		c.uris[track.Uri] = true
+	}
+
+	for i, name := range track.Artists {
+		// Local files have no artist ids
+		key := name
+		if i < len(track.ArtistIds) && track.ArtistIds[i] != "" {
+			key = track.ArtistIds[i]
+		}
+		c.artists[key]++
+		c.names[key] = name
+	}
+	if track.AlbumId != "" {
+		c.albums[track.AlbumId] = true
+	} else if track.Album != "" {
+		c.albums[track.Album] = true
+	}
+
+	for _, artist := range track.ArtistDetails {
+		for _, genre := range artist.Genres {
+			c.genres[genre]++
+		}
+	}
+	if len(track.ReleaseDate) >= 4 {
+		if year, err := strconv.Atoi(track.ReleaseDate[:4]); err == nil {
+			c.decades[strconv.Itoa(year/10*10)+"s"]++
+		}
+	}
+	if c.playlist.Collaborative && track.AddedBy != "" {
+		c.adders[track.AddedBy]++
+	}
+
+	if features := track.AudioFeatures; features != nil {
+		c.features.Tracks++
+		c.features.Danceability += features.Danceability
+		c.features.Energy += features.Energy
+		c.features.Valence += features.Valence
+		c.features.Tempo += features.Tempo
+		c.features.Loudness += features.Loudness
+	}
+
+	return nil
+}
+
+func (c *Collector) EndPlaylist() error {
+	return nil
+}
+
+func (c *Collector) Close() error {
+	return nil
+}
+
+// Stats returns the statistics of the tracks collected so far, the rankings
+// holding the top entries
+func (c *Collector) Stats(top int) *Stats {
+	stats := c.stats
+	stats.UniqueTracks = len(c.uris)
+	stats.Artists = len(c.artists)
+	stats.Albums = len(c.albums)
+	if stats.Tracks > 0 {
+		stats.ExplicitRatio = float64(stats.Explicit) / float64(stats.Tracks)
+	}
+
+	artists := map[string]int{}
+	for key, count := range c.artists {
+		artists[c.names[key]] += count
+	}
+	stats.TopArtists = rank(artists, top)
+	stats.TopGenres = rank(c.genres, top)
+	stats.TopAdders = rank(c.adders, top)
+
+	stats.Decades = rank(c.decades, len(c.decades))
+	sort.Slice(stats.Decades, func(i, j int) bool {
+		return stats.Decades[i].Name < stats.Decades[j].Name
+	})
+
+	if count := float64(c.features.Tracks); count > 0 {
+		stats.Features = &Features{
+			Tracks:       c.features.Tracks,
+			Danceability: c.features.Danceability / count,
+			Energy:       c.features.Energy / count,
+			Valence:      c.features.Valence / count,
+			Tempo:        c.features.Tempo / count,
+			Loudness:     c.features.Loudness / count,
+		}
+	}
+
+	return &stats
+}
+
+// rank returns the top entries by descending count, then by name
+func rank(counts map[string]int, top int) []Count {
+	ranking := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		ranking = append(ranking, Count{name, count})
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Count != ranking[j].Count {
+			return ranking[i].Count > ranking[j].Count
+		}
+		return ranking[i].Name < ranking[j].Name
+	})
+
+	return ranking[:min(top, len(ranking))]
+}
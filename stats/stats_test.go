@@ -0,0 +1,79 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"prisco.dev/spotify-playlist/export"
+)
+
+func TestCollector(t *testing.T) {
+	t.Run("it should compute the statistics of the tracks", func(t *testing.T) {
+		// Given a playlist and a collaborative playlist sharing a track
+		shared := export.Track{
+			Uri: "spotify:track:1", Artists: []string{"Artist"}, ArtistIds: []string{"a1"}, AlbumId: "al1",
+			ReleaseDate: "1994-05-01", DurationMs: 3600000, Explicit: true, AddedBy: "alice",
+			ArtistDetails: []export.ArtistDetails{{Id: "a1", Genres: []string{"rock", "grunge"}}},
+			AudioFeatures: &export.AudioFeatures{Energy: 0.8, Tempo: 120},
+		}
+		other := export.Track{
+			Uri: "spotify:track:2", Artists: []string{"Other", "Artist"}, ArtistIds: []string{"a2", "a1"}, AlbumId: "al2",
+			ReleaseDate: "2003", DurationMs: 1800000, AddedBy: "bob",
+			ArtistDetails: []export.ArtistDetails{{Id: "a2", Genres: []string{"rock"}}},
+			AudioFeatures: &export.AudioFeatures{Energy: 0.4, Tempo: 100},
+		}
+		local := export.Track{Type: export.TypeLocal, Uri: "spotify:local:x", Artists: []string{"Someone"}, Album: "Tape", AddedBy: "bob"}
+
+		collector := NewCollector()
+		write(collector, export.Playlist{Id: "p1"}, shared, other)
+		write(collector, export.Playlist{Id: "p2", Collaborative: true}, shared, other, local)
+
+		// When computing the statistics
+		stats := collector.Stats(2)
+
+		// Then the totals should count every track
+		if stats.Playlists != 2 || stats.Tracks != 5 || stats.UniqueTracks != 3 || stats.DurationMs != 10800000 {
+			t.Errorf("Unexpected totals %+v", stats)
+		}
+		if stats.Artists != 3 || stats.Albums != 3 || stats.Explicit != 2 || stats.ExplicitRatio != 0.4 {
+			t.Errorf("Unexpected counts %+v", stats)
+		}
+
+		// and the rankings keep the top entries
+		expectCounts(t, stats.TopArtists, []Count{{"Artist", 4}, {"Other", 2}})
+		expectCounts(t, stats.TopGenres, []Count{{"rock", 4}, {"grunge", 2}})
+		expectCounts(t, stats.Decades, []Count{{"1990s", 2}, {"2000s", 2}})
+
+		// and only the collaborative playlists should count the adders
+		expectCounts(t, stats.TopAdders, []Count{{"bob", 2}, {"alice", 1}})
+
+		// and the features averaged over the enriched tracks
+		if stats.Features == nil || stats.Features.Tracks != 4 || stats.Features.Tempo != 110 {
+			t.Errorf("Unexpected features %+v", stats.Features)
+		}
+	})
+
+	t.Run("it should leave the features out without enrichment", func(t *testing.T) {
+		stats := NewCollector().Stats(DefaultTop)
+
+		if stats.Features != nil || stats.ExplicitRatio != 0 || len(stats.TopArtists) != 0 {
+			t.Errorf("Unexpected stats %+v", stats)
+		}
+	})
+}
+
+// Helpers
+func write(collector *Collector, playlist export.Playlist, tracks ...export.Track) {
+	collector.BeginPlaylist(playlist)
+	for _, track := range tracks {
+		collector.WriteTrack(track)
+	}
+	collector.EndPlaylist()
+}
+
+func expectCounts(t *testing.T, counts []Count, expected []Count) {
+	t.Helper()
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected %v, got %v", expected, counts)
+	}
+}